@@ -0,0 +1,308 @@
+// Package initializr is a client of the Spring Initializr, e.g.
+// start.spring.io, which fetches the metadata of the server and
+// generates Spring Boot projects:
+//
+//	c := &initializr.Client{}
+//	m, err := c.Metadata()
+//	...
+//	archive, err := c.Generate(m, initializr.GenerateRequest{
+//		GroupId:      "com.acme",
+//		ArtifactId:   "orders",
+//		Dependencies: []string{"web", "actuator"},
+//	})
+//	...
+//	defer archive.Close()
+//
+// The empty fields of a request are filled with the defaults of the
+// server.
+package initializr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DefaultURL is the url of the public Spring Initializr server.
+const DefaultURL = "https://start.spring.io"
+
+// Client sends the requests to a Spring Initializr server.
+type Client struct {
+	// HTTPClient sends the requests. http.DefaultClient is used if
+	// it is nil.
+	HTTPClient *http.Client
+	// URL is the url of the server, DefaultURL if it is empty. It
+	// may have a path if the server is not at the root, e.g.
+	// https://example.com/initializr.
+	URL string
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+func (c *Client) baseURL() string {
+	if c.URL == "" {
+		return DefaultURL
+	}
+	return strings.TrimSuffix(c.URL, "/")
+}
+
+// Metadata fetches the metadata of the server.
+func (c *Client) Metadata() (*Metadata, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL()+"/metadata/client", nil)
+	if err != nil {
+		return nil, err
+	}
+	// Older servers do not support the latest metadata format,
+	// so the previous ones are accepted too.
+	req.Header.Add("Accept", "application/vnd.initializr.v2.2+json, "+
+		"application/vnd.initializr.v2.1+json;q=0.9, application/json;q=0.8")
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the metadata of %s: %s", c.baseURL(), resp.Status)
+	}
+
+	m := &Metadata{}
+	if err := json.NewDecoder(resp.Body).Decode(m); err != nil {
+		return nil, fmt.Errorf("%s is not a Spring Initializr server: %w", c.baseURL(), err)
+	}
+	// A server without project types can not generate anything,
+	// e.g. a login page of a proxy which is valid json.
+	if len(m.ProjectType.Values) == 0 {
+		return nil, fmt.Errorf("%s is not a Spring Initializr server: the metadata has no project types", c.baseURL())
+	}
+	m.APIVersion = apiVersion(resp.Header.Get("Content-Type"))
+	// 110 is the warning of a cache which serves a stale response.
+	m.Stale = strings.HasPrefix(resp.Header.Get("Warning"), "110 ")
+	return m, nil
+}
+
+// apiVersion returns the version of the metadata format from its
+// media type, e.g. v2.2 for application/vnd.initializr.v2.2+json.
+func apiVersion(contentType string) string {
+	const prefix = "application/vnd.initializr."
+	contentType, _, _ = strings.Cut(contentType, ";")
+	if !strings.HasPrefix(contentType, prefix) {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(contentType, prefix), "+json")
+}
+
+// Dependencies are the dependencies resolved for a spring boot
+// version, as served at https://start.spring.io/dependencies.
+type Dependencies struct {
+	BootVersion  string
+	Dependencies map[string]ResolvedDependency
+	Repositories map[string]Repository
+	Boms         map[string]Bom
+}
+
+// ResolvedDependency is the coordinates of a dependency with the BOM
+// and the repository which it needs, keyed by their ids.
+type ResolvedDependency struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	// Scope is the scope of the dependency, e.g. runtime or
+	// annotationProcessor.
+	Scope      string
+	Bom        string
+	Repository string
+}
+
+type Repository struct {
+	// Id is the id of the repository, which is only set by Extras.
+	Id   string `json:"-"`
+	Name string
+	Url  string
+}
+
+type Bom struct {
+	GroupId      string
+	ArtifactId   string
+	Version      string
+	Repositories []string
+}
+
+// Extras returns the BOMs and the repositories which a build file
+// needs for the dependencies.
+func (d *Dependencies) Extras(ids []string) (boms []Bom, repos []Repository) {
+	seenBom := make(map[string]bool)
+	seenRepo := make(map[string]bool)
+	addRepo := func(id string) {
+		if repo, ok := d.Repositories[id]; ok && !seenRepo[id] {
+			seenRepo[id] = true
+			repo.Id = id
+			repos = append(repos, repo)
+		}
+	}
+
+	for _, id := range ids {
+		dep, ok := d.Dependencies[id]
+		if !ok {
+			continue
+		}
+		if dep.Repository != "" {
+			addRepo(dep.Repository)
+		}
+		if bom, ok := d.Boms[dep.Bom]; ok && !seenBom[dep.Bom] {
+			seenBom[dep.Bom] = true
+			boms = append(boms, bom)
+			for _, repo := range bom.Repositories {
+				addRepo(repo)
+			}
+		}
+	}
+	return boms, repos
+}
+
+// Dependencies resolves the dependencies for the boot version, or
+// for the default one of the server if it is empty.
+func (c *Client) Dependencies(bootVersion string) (*Dependencies, error) {
+	u := c.baseURL() + "/dependencies"
+	if bootVersion != "" {
+		u += "?bootVersion=" + url.QueryEscape(bootVersion)
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/vnd.initializr.v2.2+json")
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to resolve dependencies: %s", resp.Status)
+	}
+
+	deps := &Dependencies{}
+	if err := json.NewDecoder(resp.Body).Decode(deps); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// GenerateRequest describes the project to generate. The empty
+// fields are filled with the defaults of the server.
+type GenerateRequest struct {
+	// Type is the id of the type of project, e.g. maven-project.
+	Type        string
+	Language    string
+	BootVersion string
+	Packaging   string
+	JavaVersion string
+
+	GroupId     string
+	ArtifactId  string
+	Version     string
+	Name        string
+	Description string
+	PackageName string
+	// BaseDir is the directory of the project in the archive.
+	BaseDir string
+
+	// Dependencies are the ids of the dependencies, e.g. web.
+	Dependencies []string
+	// Params holds the values of the additional fields declared by
+	// the server, keyed by the id of the field.
+	Params map[string]string
+}
+
+// Form returns the form values which request the project from the
+// server.
+func (r GenerateRequest) Form() url.Values {
+	params := []struct{ key, val string }{
+		{"name", r.Name},
+		{"groupId", r.GroupId},
+		{"artifactId", r.ArtifactId},
+		{"version", r.Version},
+		{"description", r.Description},
+		{"packageName", r.PackageName},
+		{"baseDir", r.BaseDir},
+
+		{"language", r.Language},
+		{"javaVersion", r.JavaVersion},
+		{"bootVersion", r.BootVersion},
+		{"type", r.Type},
+		{"packaging", r.Packaging},
+
+		{"dependencies", strings.Join(r.Dependencies, ",")},
+	}
+	var ids []string
+	for id := range r.Params {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		params = append(params, struct{ key, val string }{id, r.Params[id]})
+	}
+
+	// Empty values are left out so that the server applies
+	// its own defaults.
+	form := url.Values{}
+	for _, p := range params {
+		if p.val != "" {
+			form.Add(p.key, p.val)
+		}
+	}
+	return form
+}
+
+// Fetch requests the project with the given action of the server,
+// e.g. /pom.xml to get only the build file. The caller closes the
+// body of the response.
+func (c *Client) Fetch(action string, r GenerateRequest) (*http.Response, error) {
+	return c.FetchContext(context.Background(), action, r)
+}
+
+// FetchContext is Fetch which is canceled with the context, along
+// with the read of the body.
+func (c *Client) FetchContext(ctx context.Context, action string, r GenerateRequest) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL()+action,
+		strings.NewReader(r.Form().Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c.httpClient().Do(req)
+}
+
+// Generate generates the project and returns the body of its
+// archive, a zip or a tgz as the action of the project type says,
+// e.g. /starter.zip. The archive is streamed from the server rather
+// than read into memory, and the caller closes it.
+func (c *Client) Generate(m *Metadata, r GenerateRequest) (io.ReadCloser, error) {
+	projectType := r.Type
+	if projectType == "" {
+		projectType = m.ProjectType.Default
+	}
+	action, err := m.Action(projectType, "project")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Fetch(action, r)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, errors.New("failed to generate project: " + resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,378 @@
+package initializr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// Metadata is the metadata of a server which describes the fields of
+// a project, as served at https://start.spring.io/metadata/client.
+type Metadata struct {
+	Language    SelectField
+	JavaVersion SelectField
+	BootVersion SelectField
+	Packaging   SelectField
+	ProjectType ProjectTypeField `json:"type"`
+
+	GroupId      TextField
+	ArtifactId   TextField
+	Version      TextField
+	Name         TextField
+	Description  TextField
+	Dependencies DependencyField
+	Links        Links `json:"_links"`
+
+	// Unknown holds the raw fields which are not known to
+	// start.spring.io but declared by the server, so that newer
+	// metadata features are not lost while decoding.
+	Unknown map[string]json.RawMessage `json:"-"`
+
+	// APIVersion is the version of the metadata format which
+	// the server responded with, e.g. v2.2.
+	APIVersion string `json:"-"`
+
+	// Stale is set if a cache served the metadata because the server
+	// could not be reached.
+	Stale bool `json:"-"`
+
+	// fields are the ids of all the fields in the metadata.
+	fields map[string]bool
+}
+
+func (m *Metadata) UnmarshalJSON(b []byte) error {
+	// plain has the fields of Metadata without this method so
+	// that it can be decoded as usual.
+	type plain Metadata
+	if err := json.Unmarshal(b, (*plain)(m)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	m.Unknown = make(map[string]json.RawMessage)
+	m.fields = make(map[string]bool)
+	for id, raw := range fields {
+		m.fields[id] = true
+		if !knownFields[id] {
+			m.Unknown[id] = raw
+		}
+	}
+	return nil
+}
+
+// knownFields are the fields of the metadata of start.spring.io.
+var knownFields = map[string]bool{
+	"_links": true, "dependencies": true, "type": true, "packaging": true,
+	"javaVersion": true, "language": true, "bootVersion": true,
+	"groupId": true, "artifactId": true, "version": true, "name": true,
+	"description": true, "packageName": true,
+}
+
+// Supports checks if the server declares the field. Older and
+// custom servers may lack some of the fields of start.spring.io.
+func (m *Metadata) Supports(field string) bool {
+	return m.fields[field]
+}
+
+// Action returns the action of the server which generates the
+// project of the given type and format, e.g. /pom.xml for the
+// maven-build type of build format.
+func (m *Metadata) Action(projectType, format string) (string, error) {
+	var types []string
+	for _, pv := range m.ProjectType.Values {
+		if pv.Tags["format"] != format {
+			continue
+		}
+		if pv.Id == projectType {
+			if pv.Action != "" {
+				return pv.Action, nil
+			}
+			// Fall back to the action link of the type.
+			if l, ok := m.Links.First(pv.Id); ok {
+				if u, err := url.Parse(l.URL()); err == nil {
+					return u.Path, nil
+				}
+			}
+			if format == "project" {
+				return "/starter.zip", nil
+			}
+		}
+		types = append(types, pv.Id)
+	}
+	return "", fmt.Errorf("unknown %s type '%s', available types: %s",
+		format, projectType, strings.Join(types, ", "))
+}
+
+// BuildType returns the type of build format which matches the
+// build tool and the dialect of the given project type, e.g.
+// maven-build for maven-project. The type is returned as it is if
+// there is no such type.
+func (m *Metadata) BuildType(projectType string) string {
+	for _, pv := range m.ProjectType.Values {
+		if pv.Id != projectType || pv.Tags["format"] == "build" {
+			continue
+		}
+		for _, bv := range m.ProjectType.Values {
+			if bv.Tags["format"] == "build" && bv.Tags["build"] == pv.Tags["build"] &&
+				bv.Tags["dialect"] == pv.Tags["dialect"] {
+				return bv.Id
+			}
+		}
+	}
+	return projectType
+}
+
+// TextField is a field of free text with its default.
+type TextField struct {
+	Default string
+}
+
+// SelectField is a field with a value of the given ones.
+type SelectField struct {
+	Default string
+	Values  []Value
+}
+
+type Value struct {
+	Id          string
+	Name        string
+	Description string
+	Links       Links `json:"_links"`
+}
+
+// ProjectTypeField is the field of the types of project, each of
+// which is generated by an action of the server.
+type ProjectTypeField struct {
+	Default string
+	Values  []ProjectType
+}
+
+type ProjectType struct {
+	Value
+	Action string
+	Tags   map[string]string
+}
+
+// DependencyField is the field of the dependencies in their groups.
+type DependencyField struct {
+	Values []DependencyGroup
+}
+
+type DependencyGroup struct {
+	Name   string
+	Values []Dependency
+}
+
+type Dependency struct {
+	Id          string
+	Name        string
+	Description string
+	// VersionRange is the range of the spring boot versions which
+	// support the dependency.
+	VersionRange VersionRange
+	Links        Links `json:"_links"`
+	// Facets are the capabilities of the dependency, e.g. reactive.
+	// Not every server declares them.
+	Facets []string
+}
+
+// Facets returns the sorted facets of all the dependencies.
+func (f DependencyField) Facets() []string {
+	seen := make(map[string]bool)
+	var facets []string
+	for _, group := range f.Values {
+		for _, dep := range group.Values {
+			for _, facet := range dep.Facets {
+				if !seen[facet] {
+					seen[facet] = true
+					facets = append(facets, facet)
+				}
+			}
+		}
+	}
+	sort.Strings(facets)
+	return facets
+}
+
+// Link is a hypermedia link of the metadata, e.g. the action
+// to generate a type of project or the reference documentation
+// of a dependency.
+type Link struct {
+	Href      string
+	Templated bool
+	Title     string
+}
+
+// URL returns the href of the link without the variables of
+// the uri template.
+func (l Link) URL() string {
+	if i := strings.IndexByte(l.Href, '{'); l.Templated && i >= 0 {
+		return l.Href[:i]
+	}
+	return l.Href
+}
+
+// Links are the link sections of the metadata keyed by the
+// relation. A relation can have a single link or an array of
+// links.
+type Links map[string][]Link
+
+func (l *Links) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*l = make(Links)
+	for rel, r := range raw {
+		var single Link
+		if err := json.Unmarshal(r, &single); err == nil {
+			(*l)[rel] = []Link{single}
+			continue
+		}
+		var many []Link
+		if err := json.Unmarshal(r, &many); err != nil {
+			return err
+		}
+		(*l)[rel] = many
+	}
+	return nil
+}
+
+// First returns the first link of the relation.
+func (l Links) First(rel string) (Link, bool) {
+	if len(l[rel]) == 0 {
+		return Link{}, false
+	}
+	return l[rel][0], true
+}
+
+// VersionRange is a range of spring boot versions, e.g.
+// [3.2.0,3.4.0) in the notation of maven.
+type VersionRange struct {
+	Lower, Upper               string
+	LowerInclude, UpperInclude bool
+}
+
+// Contains checks if the version range contains the
+// given spring boot version. If a version can not be parsed,
+// the range is assumed to contain it.
+func (vr VersionRange) Contains(v string) bool {
+	if vr.Lower == "" && vr.Upper == "" {
+		return true
+	}
+	bv, err := version.NewSemver(normalizeVersion(v))
+	if err != nil {
+		return true
+	}
+	lowerOk, upperOk := true, true
+
+	if vr.Lower != "" {
+		lv, err := version.NewSemver(normalizeVersion(vr.Lower))
+		if err == nil && (bv.LessThan(lv) || !vr.LowerInclude && bv.Equal(lv)) {
+			lowerOk = false
+		}
+	}
+
+	if vr.Upper != "" {
+		uv, err := version.NewSemver(normalizeVersion(vr.Upper))
+		if err == nil && (bv.GreaterThan(uv) || !vr.UpperInclude && bv.Equal(uv)) {
+			upperOk = false
+		}
+	}
+	return lowerOk && upperOk
+}
+
+// normalizeVersion converts a spring boot version of the older
+// metadata format (e.g. 2.1.0.RELEASE, 2.1.0.M1 or
+// 2.1.0.BUILD-SNAPSHOT) to semver.
+func normalizeVersion(v string) string {
+	v = strings.TrimSuffix(v, ".RELEASE")
+	v = strings.Replace(v, ".BUILD-SNAPSHOT", "-SNAPSHOT", 1)
+	if parts := strings.SplitN(v, ".", 4); len(parts) == 4 {
+		v = strings.Join(parts[:3], ".") + "-" + parts[3]
+	}
+	return v
+}
+
+func (vr VersionRange) String() string {
+	if vr.Lower == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('>')
+	if vr.LowerInclude {
+		sb.WriteByte('=')
+	}
+	sb.WriteString(vr.Lower)
+
+	if vr.Upper != "" {
+		sb.WriteString(" and ")
+		sb.WriteByte('<')
+		if vr.UpperInclude {
+			sb.WriteByte('=')
+		}
+		sb.WriteString(vr.Upper)
+	}
+	return sb.String()
+}
+
+// UnmarshalJSON parses the range from a string like
+// [3.0.0,3.3.0-M1) or 3.0.0. An empty or a null range leaves it
+// empty, which contains every version.
+func (vr *VersionRange) UnmarshalJSON(b []byte) error {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("invalid version range: %w", err)
+	}
+	*vr = VersionRange{}
+	if s == nil {
+		return nil
+	}
+	r, err := ParseVersionRange(*s)
+	if err != nil {
+		return err
+	}
+	*vr = r
+	return nil
+}
+
+// ParseVersionRange parses a range of spring boot versions like
+// [3.0.0,3.3.0-M1) or 3.0.0, which is the lower bound alone. An
+// empty string is the empty range.
+func ParseVersionRange(s string) (VersionRange, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return VersionRange{}, nil
+	}
+	if s[0] != '[' && s[0] != '(' {
+		if strings.ContainsAny(s, "[](),") {
+			return VersionRange{}, fmt.Errorf("invalid version range '%s'", s)
+		}
+		return VersionRange{Lower: s, LowerInclude: true}, nil
+	}
+
+	invalid := fmt.Errorf("invalid version range '%s', should be like [3.0.0,3.3.0)", s)
+	last := s[len(s)-1]
+	if len(s) < 2 || (last != ']' && last != ')') {
+		return VersionRange{}, invalid
+	}
+	lower, upper, ok := strings.Cut(s[1:len(s)-1], ",")
+	lower, upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+	if !ok || lower == "" || upper == "" {
+		return VersionRange{}, invalid
+	}
+	return VersionRange{
+		Lower:        lower,
+		LowerInclude: s[0] == '[',
+		Upper:        upper,
+		UpperInclude: last == ']',
+	}, nil
+}
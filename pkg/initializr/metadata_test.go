@@ -0,0 +1,98 @@
+package initializr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVersionRangeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    VersionRange
+		wantErr bool
+	}{
+		{json: `"[3.0.0,3.3.0-M1)"`, want: VersionRange{Lower: "3.0.0", LowerInclude: true, Upper: "3.3.0-M1"}},
+		{json: `"(3.0.0,3.3.0]"`, want: VersionRange{Lower: "3.0.0", Upper: "3.3.0", UpperInclude: true}},
+		{json: `"3.2.0"`, want: VersionRange{Lower: "3.2.0", LowerInclude: true}},
+		{json: `""`, want: VersionRange{}},
+		{json: `null`, want: VersionRange{}},
+		{json: `"["`, wantErr: true},
+		{json: `"[3.0.0"`, wantErr: true},
+		{json: `"[3.0.0)"`, wantErr: true},
+		{json: `"[,3.0.0)"`, wantErr: true},
+		{json: `"3.0.0,3.1.0"`, wantErr: true},
+		{json: `42`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var vr VersionRange
+		err := json.Unmarshal([]byte(tt.json), &vr)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: got %+v, want an error", tt.json, vr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.json, err)
+		} else if vr != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.json, vr, tt.want)
+		}
+	}
+}
+
+func TestDependencyEmptyVersionRange(t *testing.T) {
+	var dep struct {
+		VersionRange VersionRange
+	}
+	if err := json.Unmarshal([]byte(`{"versionRange": ""}`), &dep); err != nil {
+		t.Fatal(err)
+	}
+	if !dep.VersionRange.Contains("3.3.4") {
+		t.Error("an empty range does not contain 3.3.4")
+	}
+}
+
+func TestVersionRangeContains(t *testing.T) {
+	tests := []struct {
+		rng     VersionRange
+		version string
+		want    bool
+	}{
+		// [3.0.0,3.3.0)
+		{VersionRange{Lower: "3.0.0", LowerInclude: true, Upper: "3.3.0"}, "2.7.18", false},
+		{VersionRange{Lower: "3.0.0", LowerInclude: true, Upper: "3.3.0"}, "3.0.0", true},
+		{VersionRange{Lower: "3.0.0", LowerInclude: true, Upper: "3.3.0"}, "3.2.5", true},
+		{VersionRange{Lower: "3.0.0", LowerInclude: true, Upper: "3.3.0"}, "3.3.0", false},
+		// (3.0.0,3.3.0]
+		{VersionRange{Lower: "3.0.0", Upper: "3.3.0", UpperInclude: true}, "3.0.0", false},
+		{VersionRange{Lower: "3.0.0", Upper: "3.3.0", UpperInclude: true}, "3.0.1", true},
+		{VersionRange{Lower: "3.0.0", Upper: "3.3.0", UpperInclude: true}, "3.3.0", true},
+		{VersionRange{Lower: "3.0.0", Upper: "3.3.0", UpperInclude: true}, "3.3.1", false},
+		// A bare version is an inclusive lower bound.
+		{VersionRange{Lower: "3.2.0", LowerInclude: true}, "3.1.9", false},
+		{VersionRange{Lower: "3.2.0", LowerInclude: true}, "3.2.0", true},
+		{VersionRange{Lower: "4.0.0", LowerInclude: true}, "4.1.0", true},
+		// The pre-releases come before their release.
+		{VersionRange{Lower: "3.0.0", LowerInclude: true, Upper: "3.3.0-M1"}, "3.3.0-M1", false},
+		{VersionRange{Lower: "3.0.0", LowerInclude: true, Upper: "3.3.0-M1"}, "3.2.9", true},
+		{VersionRange{Lower: "3.3.0", LowerInclude: true}, "3.3.0-M1", false},
+		{VersionRange{Lower: "3.3.0", LowerInclude: true}, "3.3.0-RC1", false},
+		{VersionRange{Lower: "3.3.0", LowerInclude: true}, "3.3.0-SNAPSHOT", false},
+		{VersionRange{Lower: "3.3.0-M1", LowerInclude: true}, "3.3.0-M1", true},
+		{VersionRange{Lower: "3.3.0-M1", LowerInclude: true}, "3.3.0-RC1", true},
+		{VersionRange{Lower: "3.3.0-M1", LowerInclude: true}, "3.3.0-SNAPSHOT", true},
+		{VersionRange{Lower: "3.3.0-RC1", LowerInclude: true}, "3.3.0-M2", false},
+		{VersionRange{Lower: "3.0.0", LowerInclude: true, Upper: "3.3.0-RC1"}, "3.3.0-M3", true},
+		// The versions of the older metadata format.
+		{VersionRange{Lower: "2.1.0.RELEASE", LowerInclude: true, Upper: "2.2.0.M1"}, "2.1.0.RELEASE", true},
+		{VersionRange{Lower: "2.1.0.RELEASE", LowerInclude: true, Upper: "2.2.0.M1"}, "2.2.0.M1", false},
+		{VersionRange{Lower: "2.1.0.RELEASE", LowerInclude: true}, "2.1.0.BUILD-SNAPSHOT", false},
+		{VersionRange{}, "3.3.0", true},
+		{VersionRange{Lower: "3.0.0", LowerInclude: true}, "not a version", true},
+	}
+	for _, tt := range tests {
+		if got := tt.rng.Contains(tt.version); got != tt.want {
+			t.Errorf("%+v contains %s: got %v, want %v", tt.rng, tt.version, got, tt.want)
+		}
+	}
+}
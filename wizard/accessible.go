@@ -0,0 +1,79 @@
+package wizard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// runAccessible asks the questions of the form one per line with the
+// accessible mode of huh instead of the styled UI, and then generates
+// the project as with -no-input.
+func runAccessible(data *metadata, client *initializr.Client, info *projectInfo, opts options) {
+	if opts.explore {
+		die(errors.New("-explore needs the styled UI and can not be used with -accessible"))
+	}
+	// huh prints the questions of the accessible mode to os.Stdout, so
+	// it is pointed at the prompt output while they are asked.
+	stdout := os.Stdout
+	os.Stdout = promptOutput()
+	askAccessible(data, info, opts)
+	os.Stdout = stdout
+
+	if opts.web {
+		applyDefaults(info, data)
+		if err := openBrowser(shareURL(client.URL, info)); err != nil {
+			die(err)
+		}
+		fmt.Printf("Opened %s\n", shareURL(client.URL, info))
+		return
+	}
+	runHeadless(data, client, info, opts)
+	if !opts.share {
+		if err := saveLastAnswers(info); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to remember the answers: "+err.Error())
+		}
+	}
+}
+
+// askAccessible asks the questions of the form in accessible mode.
+func askAccessible(data *metadata, info *projectInfo, opts options) {
+	if len(opts.profiles) > 0 {
+		var preset string
+		if err := newPresetForm(profileNames(opts.profiles), &preset, opts).WithAccessible(true).Run(); err != nil {
+			die(err)
+		}
+		if cfg, ok := opts.profiles[preset]; ok {
+			info.fill(cfg.projectInfo())
+		}
+	}
+	form, _ := newForm(info, data, opts)
+	if err := form.WithAccessible(true).Run(); err != nil {
+		die(err)
+	}
+	// huh adds the choices of a multi-select twice in accessible mode.
+	info.addOns = unique(info.addOns)
+}
+
+// promptOutput returns where the accessible mode writes its questions:
+// stdout if it is a terminal, otherwise stderr, so that a piped stdout,
+// e.g. with -json -, only holds the output of the command.
+func promptOutput() *os.File {
+	if fi, err := os.Stdout.Stat(); err == nil && fi.Mode()&os.ModeCharDevice == 0 {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// unique returns the values without the duplicates, in their order.
+func unique(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if !contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,171 @@
+package wizard
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// runAdd runs the add command which adds dependencies to the build
+// file of an existing project, with the BOMs and the repositories
+// which they need.
+func runAdd(args []string) {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: startspring add [flags]")
+		fs.PrintDefaults()
+	}
+	dir := fs.String("dir", ".", "directory of the project")
+	var ids listFlag
+	fs.Var(&ids, "deps", "comma separated dependency ids to add, picked from a list if empty")
+	dryRun := fs.Bool("dry-run", false, "print the changes of the build file as a diff instead of writing them")
+	plain := fs.Bool("plain", false, "use a compact and colorless UI for limited terminals")
+	var httpOpts httpOptions
+	httpFlags(fs, &httpOpts)
+	fs.Parse(args)
+
+	path, err := buildFilePath(*dir)
+	if err != nil {
+		die(err)
+	}
+	declared, bootVersion, err := readBuildFile(*dir)
+	if err != nil {
+		die(err)
+	}
+
+	client, err := newClient(httpOpts)
+	if err != nil {
+		die(err)
+	}
+	data, err := getMetaData(client)
+	if err != nil {
+		die(err)
+	}
+	if bootVersion == "" {
+		bootVersion = data.BootVersion.Default
+		fmt.Fprintln(os.Stderr, warnStyle.Render(
+			"The spring boot version is not found in the build file, using "+bootVersion))
+	}
+	deps, err := getDependencies(client, bootVersion)
+	if err != nil {
+		die(err)
+	}
+
+	present := make(map[string]bool)
+	for _, d := range declared {
+		present[d.coords] = true
+	}
+	isPresent := func(id string) bool {
+		dep := deps.Dependencies[id]
+		return present[dep.GroupId+":"+dep.ArtifactId]
+	}
+
+	if len(ids) == 0 {
+		title := "Add dependencies"
+		description := "Spring Boot " + bootVersion + ", the declared dependencies are not shown"
+		if ids, err = pickDependencies(data, deps, bootVersion, isPresent, title, description, *plain); err != nil {
+			die(err)
+		}
+	}
+	var added []string
+	for _, id := range ids {
+		switch _, ok := deps.Dependencies[id]; {
+		case !ok:
+			die(fmt.Errorf("unknown dependency '%s' for Spring Boot %s", id, bootVersion))
+		case isPresent(id):
+			fmt.Printf("%s is already declared\n", id)
+		default:
+			added = append(added, id)
+		}
+	}
+	if len(added) == 0 {
+		fmt.Println("Nothing to add")
+		return
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		die(err)
+	}
+	patched, err := patchBuildFile(path, string(b), additionOf(deps, added))
+	if err != nil {
+		die(err)
+	}
+	if *dryRun {
+		fmt.Print(unifiedDiff(path, path, string(b), patched))
+		return
+	}
+	if err := os.WriteFile(path, []byte(patched), 0644); err != nil {
+		die(err)
+	}
+	fmt.Printf("Added %s to %s\n", strings.Join(added, ", "), path)
+	boms, repos := extras(deps, added)
+	if len(boms) > 0 {
+		fmt.Println("Imported BOMs: " + strings.Join(boms, ", "))
+	}
+	if len(repos) > 0 {
+		fmt.Println("Added repositories: " + strings.Join(repos, ", "))
+	}
+}
+
+// buildFilePath returns the path of the build file of the project in
+// the directory.
+func buildFilePath(dir string) (string, error) {
+	for _, name := range []string{"pom.xml", "build.gradle", "build.gradle.kts"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no pom.xml or build.gradle in %s", dir)
+}
+
+// pickDependencies lets the user pick dependencies from the ones
+// which are compatible with the boot version and not declared yet.
+func pickDependencies(data *metadata, deps *initializr.Dependencies, bootVersion string,
+	isPresent func(id string) bool, title, description string, plain bool) ([]string, error) {
+	var options []depOption
+	for _, values := range data.Dependencies.Values {
+		for _, dep := range values.Values {
+			if _, ok := deps.Dependencies[dep.Id]; !ok || isPresent(dep.Id) ||
+				!dep.VersionRange.Contains(bootVersion) {
+				continue
+			}
+			options = append(options, depOption{
+				id: dep.Id, label: dep.Name, group: values.Name, description: dep.Description,
+			})
+		}
+	}
+	if len(options) == 0 {
+		return nil, errors.New("every dependency of the server is already declared")
+	}
+
+	var selected []string
+	height := 22
+	if plain {
+		height = 12
+	}
+	picker := newDepPicker(&selected, height)
+	picker.title = title
+	picker.description = description
+	picker.coordinates = depCoordinates(deps)
+	picker.setOptions(options)
+
+	form := huh.NewForm(huh.NewGroup(picker))
+	if plain {
+		form = form.WithTheme(plainTheme()).WithShowHelp(false)
+	} else {
+		form = form.WithTheme(huh.ThemeDracula())
+	}
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
@@ -0,0 +1,345 @@
+package wizard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// addOn adds files to the project after it is extracted.
+type addOn struct {
+	id   string
+	name string
+	// apply writes the files of the add-on into the project directory
+	// and returns the slash separated paths of the added or changed
+	// files.
+	apply func(dir string, info *projectInfo) ([]string, error)
+}
+
+// addOns are the add-ons which can be picked in the form or with the
+// -add-ons flag.
+var addOns = []addOn{
+	{"dotenv", "Secrets in .env with placeholders in the configuration", applyDotenv},
+	{"nix", "Nix flake and direnv development shell", applyNix},
+	{"docker", "Multi-stage Dockerfile and .dockerignore", applyDocker},
+	{"devcontainer", "Dev container with the JDK and the build tool", applyDevcontainer},
+}
+
+// applyAddOns applies the add-ons of the project in the given order
+// and returns the added or changed files.
+func applyAddOns(dir string, info *projectInfo) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, id := range info.addOns {
+		var a *addOn
+		for i := range addOns {
+			if addOns[i].id == id {
+				a = &addOns[i]
+			}
+		}
+		if a == nil {
+			return nil, fmt.Errorf("unknown add-on '%s'", id)
+		}
+
+		written, err := a.apply(dir, info)
+		if err != nil {
+			return nil, fmt.Errorf("add-on %s: %w", id, err)
+		}
+		for _, f := range written {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	return files, nil
+}
+
+// placeholder is a configuration property which is read from an
+// environment variable.
+type placeholder struct {
+	property string
+	env      string
+	example  string
+}
+
+func datasource(url string) []placeholder {
+	return []placeholder{
+		{"spring.datasource.url", "DB_URL", url},
+		{"spring.datasource.username", "DB_USERNAME", "app"},
+		{"spring.datasource.password", "DB_PASSWORD", "changeme"},
+	}
+}
+
+// secretPlaceholders are the placeholders of the secrets and the
+// connection settings of the dependencies.
+var secretPlaceholders = map[string][]placeholder{
+	"postgresql": datasource("jdbc:postgresql://localhost:5432/app"),
+	"mysql":      datasource("jdbc:mysql://localhost:3306/app"),
+	"mariadb":    datasource("jdbc:mariadb://localhost:3306/app"),
+	"sqlserver":  datasource("jdbc:sqlserver://localhost:1433;databaseName=app"),
+	"oracle":     datasource("jdbc:oracle:thin:@localhost:1521/app"),
+	"data-mongodb": {
+		{"spring.data.mongodb.uri", "MONGODB_URI", "mongodb://localhost:27017/app"},
+	},
+	"data-mongodb-reactive": {
+		{"spring.data.mongodb.uri", "MONGODB_URI", "mongodb://localhost:27017/app"},
+	},
+	"data-redis": {
+		{"spring.data.redis.host", "REDIS_HOST", "localhost"},
+		{"spring.data.redis.password", "REDIS_PASSWORD", "changeme"},
+	},
+	"data-redis-reactive": {
+		{"spring.data.redis.host", "REDIS_HOST", "localhost"},
+		{"spring.data.redis.password", "REDIS_PASSWORD", "changeme"},
+	},
+	"amqp": {
+		{"spring.rabbitmq.host", "RABBITMQ_HOST", "localhost"},
+		{"spring.rabbitmq.username", "RABBITMQ_USERNAME", "guest"},
+		{"spring.rabbitmq.password", "RABBITMQ_PASSWORD", "guest"},
+	},
+	"kafka": {
+		{"spring.kafka.bootstrap-servers", "KAFKA_BOOTSTRAP_SERVERS", "localhost:9092"},
+	},
+	"mail": {
+		{"spring.mail.host", "MAIL_HOST", "smtp.example.com"},
+		{"spring.mail.username", "MAIL_USERNAME", "app"},
+		{"spring.mail.password", "MAIL_PASSWORD", "changeme"},
+	},
+	"spring-ai-openai": {
+		{"spring.ai.openai.api-key", "OPENAI_API_KEY", "sk-changeme"},
+	},
+	"spring-ai-anthropic": {
+		{"spring.ai.anthropic.api-key", "ANTHROPIC_API_KEY", "changeme"},
+	},
+}
+
+// applyDotenv writes a .env.example with the secrets of the selected
+// dependencies, reads them from the environment in the configuration
+// and keeps the real .env out of git. Spring Boot imports the .env
+// itself, so no library is needed.
+func applyDotenv(dir string, info *projectInfo) ([]string, error) {
+	var placeholders []placeholder
+	seen := make(map[string]bool)
+	for _, dep := range info.dependencies {
+		for _, p := range secretPlaceholders[dep] {
+			if !seen[p.property] {
+				seen[p.property] = true
+				placeholders = append(placeholders, p)
+			}
+		}
+	}
+
+	var env strings.Builder
+	env.WriteString("# Copy to .env and fill in the values, .env is not committed.\n")
+	for _, p := range placeholders {
+		fmt.Fprintf(&env, "%s=%s\n", p.env, p.example)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env.example"), []byte(env.String()), 0644); err != nil {
+		return nil, err
+	}
+	files := []string{".env.example"}
+
+	// The keys are flat, so the same lines work in yaml.
+	config, sep := "src/main/resources/application.properties", "="
+	for _, name := range []string{"application.yaml", "application.yml"} {
+		if _, err := os.Stat(filepath.Join(dir, "src/main/resources", name)); err == nil {
+			config, sep = "src/main/resources/"+name, ": "
+		}
+	}
+	lines := []string{"spring.config.import" + sep + "optional:file:.env[.properties]"}
+	for _, p := range placeholders {
+		lines = append(lines, p.property+sep+"${"+p.env+"}")
+	}
+	if err := appendLines(filepath.Join(dir, filepath.FromSlash(config)), lines); err != nil {
+		return nil, err
+	}
+	files = append(files, config)
+
+	if err := appendLines(filepath.Join(dir, ".gitignore"), []string{"", "### Secrets ###", ".env"}); err != nil {
+		return nil, err
+	}
+	return append(files, ".gitignore"), nil
+}
+
+// nixFlake is the flake of the development shell with the JDK and
+// the build tool of the project.
+const nixFlake = `{
+  description = "Development shell of %s";
+
+  inputs = {
+    nixpkgs.url = "github:NixOS/nixpkgs/nixos-unstable";
+    flake-utils.url = "github:numtide/flake-utils";
+  };
+
+  outputs = { nixpkgs, flake-utils, ... }:
+    flake-utils.lib.eachDefaultSystem (system:
+      let
+        pkgs = nixpkgs.legacyPackages.${system};
+        jdk = pkgs.%s;
+      in {
+        devShells.default = pkgs.mkShell {
+          packages = [ jdk pkgs.%s ];
+          JAVA_HOME = jdk.home;
+        };
+      });
+}
+`
+
+// applyNix writes a flake.nix with a development shell pinned to the
+// Java version of the project and a .envrc which loads it with
+// direnv.
+func applyNix(dir string, info *projectInfo) ([]string, error) {
+	// The JDK packages of nixpkgs are named after the major version,
+	// e.g. jdk21.
+	jdk := "jdk" + javaMajorVersion(info)
+	flake := fmt.Sprintf(nixFlake, info.name, jdk, buildTool(info))
+	if err := os.WriteFile(filepath.Join(dir, "flake.nix"), []byte(flake), 0644); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".envrc"), []byte("use flake\n"), 0644); err != nil {
+		return nil, err
+	}
+	if err := appendLines(filepath.Join(dir, ".gitignore"), []string{"", "### direnv ###", ".direnv/"}); err != nil {
+		return nil, err
+	}
+	return []string{"flake.nix", ".envrc", ".gitignore"}, nil
+}
+
+// buildTool returns the build tool of the project, maven or gradle.
+func buildTool(info *projectInfo) string {
+	if strings.HasPrefix(info.projectType, "gradle") {
+		return "gradle"
+	}
+	return "maven"
+}
+
+// javaMajorVersion returns the major version of Java of the project,
+// e.g. 8 for 1.8.
+func javaMajorVersion(info *projectInfo) string {
+	return strings.TrimPrefix(info.javaVersion, "1.")
+}
+
+// dockerfile is the multi-stage Dockerfile which builds the
+// application with the wrapper of the build tool and runs it on a
+// JRE as a user without privileges.
+const dockerfile = `# syntax=docker/dockerfile:1
+
+FROM eclipse-temurin:%[1]s-jdk AS build
+WORKDIR /workspace
+%[2]s
+FROM eclipse-temurin:%[1]s-jre
+WORKDIR /app
+RUN groupadd --system spring && useradd --system --gid spring spring
+USER spring
+COPY --from=build /workspace/app.%[3]s app.%[3]s
+EXPOSE 8080
+ENTRYPOINT ["java", "-jar", "app.%[3]s"]
+`
+
+// dockerBuild are the steps of the build stage. The dependencies are
+// downloaded before the sources are copied, so that they are cached
+// until the build file changes.
+var dockerBuild = map[string]string{
+	"maven": `COPY mvnw pom.xml ./
+COPY .mvn .mvn
+RUN ./mvnw -B dependency:go-offline
+COPY src src
+RUN ./mvnw -B package -DskipTests && cp target/*.%[1]s app.%[1]s
+`,
+	"gradle": `COPY gradlew settings.gradle* build.gradle* ./
+COPY gradle gradle
+RUN ./gradlew --no-daemon dependencies > /dev/null
+COPY src src
+RUN ./gradlew --no-daemon %[2]s -x test && cp build/libs/*.%[1]s app.%[1]s
+`,
+}
+
+// dockerignore keeps the build output, the IDE files and the secrets
+// out of the build context.
+const dockerignore = `target/
+build/
+.gradle/
+.idea/
+*.iml
+.vscode/
+.env
+`
+
+// applyDocker writes a multi-stage Dockerfile for the build tool, the
+// Java version and the packaging of the project, and a .dockerignore.
+func applyDocker(dir string, info *projectInfo) ([]string, error) {
+	ext, task := "jar", "bootJar"
+	if info.packaging == "war" {
+		ext, task = "war", "bootWar"
+	}
+	build := fmt.Sprintf(dockerBuild[buildTool(info)], ext, task)
+	content := fmt.Sprintf(dockerfile, javaMajorVersion(info), build, ext)
+	if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(content), 0644); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte(dockerignore), 0644); err != nil {
+		return nil, err
+	}
+	return []string{"Dockerfile", ".dockerignore"}, nil
+}
+
+// devcontainer is the configuration of the dev container, with the
+// JDK and the build tool installed by the java feature.
+const devcontainer = `{
+  "name": %s,
+  "image": "mcr.microsoft.com/devcontainers/base:bookworm",
+  "features": {
+    "ghcr.io/devcontainers/features/java:1": {
+      "version": "%s",
+      "jdkDistro": "tem",
+      "installMaven": "%t",
+      "installGradle": "%t"
+    }
+  },
+  "forwardPorts": [8080],
+  "customizations": {
+    "vscode": {
+      "extensions": ["vscjava.vscode-java-pack", "vmware.vscode-boot-dev-pack"]
+    }
+  }
+}
+`
+
+// applyDevcontainer writes a .devcontainer/devcontainer.json with the
+// Java version and the build tool of the project.
+func applyDevcontainer(dir string, info *projectInfo) ([]string, error) {
+	name, err := json.Marshal(info.name)
+	if err != nil {
+		return nil, err
+	}
+	tool := buildTool(info)
+	content := fmt.Sprintf(devcontainer, name, javaMajorVersion(info), tool == "maven", tool == "gradle")
+	if err := os.MkdirAll(filepath.Join(dir, ".devcontainer"), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".devcontainer", "devcontainer.json"), []byte(content), 0644); err != nil {
+		return nil, err
+	}
+	return []string{".devcontainer/devcontainer.json"}, nil
+}
+
+// appendLines appends the lines to the file, creating it if it does
+// not exist.
+func appendLines(path string, lines []string) error {
+	b, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	content := string(b)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += strings.Join(lines, "\n") + "\n"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
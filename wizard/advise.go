@@ -0,0 +1,197 @@
+package wizard
+
+import (
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// advice is the outcome of a dependency of the project at the
+// target boot version.
+type advice struct {
+	ok     bool
+	id     string
+	detail string
+}
+
+// runAdvise runs the advise command which reports the dependencies
+// of an existing project which are unavailable, renamed or
+// incompatible at another boot version.
+func runAdvise(args []string) {
+	fs := flag.NewFlagSet("advise", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory of the project")
+	from := fs.String("from", "", "current spring boot version, read from the build file if empty")
+	bootVersion := fs.String("boot-version", "",
+		"spring boot version to upgrade to, e.g. 3.4.x for the latest 3.4 release")
+	var httpOpts httpOptions
+	httpFlags(fs, &httpOpts)
+	fs.Parse(args)
+	if *bootVersion == "" {
+		die(errors.New("-boot-version is required"))
+	}
+
+	deps, current, err := readBuildFile(*dir)
+	if err != nil {
+		die(err)
+	}
+	if *from != "" {
+		current = *from
+	}
+	if current == "" {
+		die(errors.New("the spring boot version is not found in the build file, set it with -from"))
+	}
+
+	client, err := newClient(httpOpts)
+	if err != nil {
+		die(err)
+	}
+	data, err := getMetaData(client)
+	if err != nil {
+		die(err)
+	}
+	target := resolveBootVersion(data, *bootVersion)
+	targetDeps, err := getDependencies(client, target)
+	if err != nil {
+		die(err)
+	}
+	// The server may not support the current version anymore, then
+	// the dependencies are recognized by their target coordinates.
+	currentDeps, err := getDependencies(client, current)
+	if err != nil {
+		currentDeps = targetDeps
+	}
+
+	advices, unknown := advise(data, currentDeps, targetDeps, deps, target)
+	fmt.Printf("Upgrading from Spring Boot %s to %s\n", current, target)
+	failed := false
+	for _, a := range advices {
+		mark := okStyle.Render("✓")
+		if !a.ok {
+			mark = failStyle.Render("✗")
+			failed = true
+		}
+		fmt.Printf("%s %s\n", mark, a.id)
+		if a.detail != "" {
+			fmt.Printf("  %s\n", a.detail)
+		}
+	}
+	if len(unknown) > 0 {
+		fmt.Println(hintStyle.Render("Not managed by the Initializr:"))
+		for _, coords := range unknown {
+			fmt.Println(hintStyle.Render("  " + coords))
+		}
+	}
+	if failed {
+		exit(1)
+	}
+}
+
+// advise maps the declared dependencies back to the ids of the
+// Initializr with their coordinates at the current version and
+// checks them at the target version. The coordinates which are not
+// mapped are returned as unknown.
+func advise(data *metadata, currentDeps, targetDeps *initializr.Dependencies, declared []buildDependency, target string) ([]advice, []string) {
+	ids := make(map[string][]string)
+	for id, dep := range currentDeps.Dependencies {
+		coords := dep.GroupId + ":" + dep.ArtifactId
+		ids[coords] = append(ids[coords], id)
+	}
+
+	var advices []advice
+	var unknown []string
+	// The base starters are added to every project.
+	seen := map[string]bool{
+		"org.springframework.boot:spring-boot-starter":      true,
+		"org.springframework.boot:spring-boot-starter-test": true,
+	}
+	for _, d := range declared {
+		if seen[d.coords] {
+			continue
+		}
+		seen[d.coords] = true
+		matched, ok := ids[d.coords]
+		if !ok {
+			unknown = append(unknown, d.coords)
+			continue
+		}
+		sort.Strings(matched)
+		for _, id := range matched {
+			advices = append(advices, adviseDependency(data, targetDeps, id, d.coords, target))
+		}
+	}
+	sort.Strings(unknown)
+	return advices, unknown
+}
+
+func adviseDependency(data *metadata, targetDeps *initializr.Dependencies, id, coords, target string) advice {
+	for _, values := range data.Dependencies.Values {
+		for _, dep := range values.Values {
+			if dep.Id != id {
+				continue
+			}
+			if !dep.VersionRange.Contains(target) {
+				return advice{id: id, detail: fmt.Sprintf(
+					"%s is incompatible, it requires Spring Boot %s", dep.Name, dep.VersionRange)}
+			}
+			resolved, ok := targetDeps.Dependencies[id]
+			if !ok {
+				return advice{id: id, detail: dep.Name + " is unavailable at the target version"}
+			}
+			if c := resolved.GroupId + ":" + resolved.ArtifactId; c != coords {
+				return advice{id: id, detail: fmt.Sprintf("%s is renamed from %s to %s", dep.Name, coords, c)}
+			}
+			return advice{ok: true, id: id}
+		}
+	}
+	return advice{id: id, detail: "no longer offered by the server"}
+}
+
+// gradleBootPlugin matches the version of the spring boot plugin in
+// the groovy or the kotlin dsl.
+var gradleBootPlugin = regexp.MustCompile(
+	`id\s*\(?\s*["']org\.springframework\.boot["']\s*\)?\s*version\s*["']([^"']+)["']`)
+
+// readBuildFile returns the dependencies and the spring boot version
+// of the build file of the project. The version is empty if it is
+// not declared.
+func readBuildFile(dir string) ([]buildDependency, string, error) {
+	if b, err := os.ReadFile(filepath.Join(dir, "pom.xml")); err == nil {
+		deps, _, err := parsePom(b)
+		if err != nil {
+			return nil, "", err
+		}
+		var pom struct {
+			Parent struct {
+				ArtifactId string `xml:"artifactId"`
+				Version    string `xml:"version"`
+			} `xml:"parent"`
+		}
+		xml.Unmarshal(b, &pom)
+		version := ""
+		if pom.Parent.ArtifactId == "spring-boot-starter-parent" {
+			version = pom.Parent.Version
+		}
+		return deps, version, nil
+	}
+
+	for _, name := range []string{"build.gradle", "build.gradle.kts"} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		deps, _ := parseGradle(b)
+		version := ""
+		if m := gradleBootPlugin.FindSubmatch(b); m != nil {
+			version = string(m[1])
+		}
+		return deps, version, nil
+	}
+	return nil, "", fmt.Errorf("no pom.xml or build.gradle in %s", dir)
+}
@@ -0,0 +1,71 @@
+package wizard
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"sync"
+)
+
+// archive is a downloaded project archive. It is kept in a temporary
+// file rather than in memory as the projects with many dependencies
+// can be large, and its entries are read from the file one by one.
+type archive struct {
+	f    *os.File
+	size int64
+}
+
+// tempArchives are the paths of the archives which are not removed
+// yet, so that they are removed even if the program is interrupted.
+var tempArchives = struct {
+	sync.Mutex
+	paths map[string]bool
+}{paths: make(map[string]bool)}
+
+// newArchive writes the archive read from r into a temporary file.
+// The file is removed if it fails.
+func newArchive(r io.Reader) (*archive, error) {
+	f, err := os.CreateTemp("", "startspring-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	tempArchives.Lock()
+	tempArchives.paths[f.Name()] = true
+	tempArchives.Unlock()
+
+	a := &archive{f: f}
+	if a.size, err = io.Copy(f, r); err != nil {
+		a.remove()
+		return nil, err
+	}
+	return a, nil
+}
+
+// reader returns the reader of the entries of the archive.
+func (a *archive) reader() (*zip.Reader, error) {
+	return zip.NewReader(a.f, a.size)
+}
+
+// remove closes and removes the temporary file. It can be called
+// more than once.
+func (a *archive) remove() {
+	if a == nil {
+		return
+	}
+	a.f.Close()
+	os.Remove(a.f.Name())
+	tempArchives.Lock()
+	delete(tempArchives.paths, a.f.Name())
+	tempArchives.Unlock()
+}
+
+// removeTempArchives removes the archives which are left, e.g. by a
+// download which was still running when the UI was quit.
+func removeTempArchives() {
+	tempArchives.Lock()
+	defer tempArchives.Unlock()
+	for p := range tempArchives.paths {
+		os.Remove(p)
+		delete(tempArchives.paths, p)
+	}
+}
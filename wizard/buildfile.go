@@ -0,0 +1,161 @@
+package wizard
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// runBuildFile runs the buildfile command which writes only the
+// build file of the project, e.g. pom.xml or build.gradle.
+func runBuildFile(args []string) {
+	info := &projectInfo{projectType: "maven-build"}
+
+	fs := flag.NewFlagSet("buildfile", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: startspring buildfile [flags]")
+		fs.PrintDefaults()
+	}
+	projectFlags(fs, info)
+	output := fs.String("o", "", "write the build file to this file instead of stdout")
+	var httpOpts httpOptions
+	httpFlags(fs, &httpOpts)
+	fs.Parse(args)
+
+	client, err := newClient(httpOpts)
+	if err != nil {
+		die(err)
+	}
+	data, err := getMetaData(client)
+	if err != nil {
+		die(err)
+	}
+	applyDefaults(info, data)
+	if err := validateCoordinates(info); err != nil {
+		die(err)
+	}
+
+	action, err := data.Action(info.projectType, "build")
+	if err != nil {
+		die(err)
+	}
+
+	resp, err := getProjectFile(context.Background(), client, action, info)
+	if err != nil {
+		die(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		die(fmt.Errorf("failed to generate build file: %s", resp.Status))
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			die(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		die(err)
+	}
+}
+
+// isBuildType checks if the project type generates only the build
+// file, e.g. maven-build.
+func (m *metadata) isBuildType(projectType string) bool {
+	for _, pv := range m.ProjectType.Values {
+		if pv.Id == projectType {
+			return pv.Tags["format"] == "build"
+		}
+	}
+	return false
+}
+
+// generateAction returns the action of the server which generates
+// the project, or only its build file for a type of build format.
+func (m *metadata) generateAction(projectType string) (string, error) {
+	if m.isBuildType(projectType) {
+		return m.Action(projectType, "build")
+	}
+	return m.Action(projectType, "project")
+}
+
+// buildTypeName returns the name of a type of build format in the
+// form, e.g. "Maven POM only".
+func buildTypeName(pt initializr.ProjectType) string {
+	switch {
+	case pt.Tags["build"] == "maven":
+		return "Maven POM only"
+	case pt.Tags["build"] == "gradle" && pt.Tags["dialect"] == "kotlin":
+		return "Gradle build file only (Kotlin)"
+	case pt.Tags["build"] == "gradle":
+		return "Gradle build file only"
+	}
+	return pt.Name + " only"
+}
+
+// buildFileTarget returns the path where the build file of the
+// action is written, which is in the output directory if it is given
+// and in the current directory otherwise.
+func buildFileTarget(action string, info *projectInfo) string {
+	dir := "."
+	if strings.TrimSpace(info.output) != "" {
+		dir = info.dir()
+	}
+	return filepath.Join(dir, path.Base(action))
+}
+
+// checkBuildFile checks that the build file can be written, which
+// replaces an existing one only if the existing option allows it.
+func checkBuildFile(p, policy string) error {
+	fi, err := os.Stat(p)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if fi.IsDir() || policy != existingOverwrite {
+		return fmt.Errorf("a file named '%s' already exists", p)
+	}
+	return nil
+}
+
+// writeBuildFile downloads only the build file of the project instead
+// of its archive and returns the path where it is written.
+func writeBuildFile(ctx context.Context, client *initializr.Client, data *metadata, info *projectInfo, existing string) (string, error) {
+	action, err := data.Action(info.projectType, "build")
+	if err != nil {
+		return "", err
+	}
+	p := buildFileTarget(action, info)
+	if err := checkBuildFile(p, existing); err != nil {
+		return "", err
+	}
+
+	resp, err := getProjectFile(ctx, client, action, info)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to generate build file: %s", resp.Status)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0777); err != nil {
+		return "", err
+	}
+	return p, os.WriteFile(p, b, 0644)
+}
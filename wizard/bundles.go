@@ -0,0 +1,58 @@
+package wizard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// bundlesFile returns the file of the dependency bundles, e.g.
+// ~/.config/startspring/bundles.yaml. A bundle is a named set of
+// dependencies which are often picked together:
+//
+//	rest-service: [web, actuator, validation, lombok]
+//	kafka-worker: [kafka, actuator]
+func bundlesFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "startspring", "bundles.yaml"), nil
+}
+
+// readBundles reads the dependency bundles. There are none if the
+// file does not exist.
+func readBundles() (map[string][]string, error) {
+	path, err := bundlesFile()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var bundles map[string][]string
+	if err := yaml.NewDecoder(bytes.NewReader(b)).Decode(&bundles); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid bundles %s: %w", path, err)
+	}
+	return bundles, nil
+}
+
+// bundleNames returns the sorted names of the bundles.
+func bundleNames(bundles map[string][]string) []string {
+	var names []string
+	for name := range bundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,164 @@
+package wizard
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultCacheTTL is how long the cached metadata is used without
+// asking the server.
+const defaultCacheTTL = 24 * time.Hour
+
+// cachedResponse is a metadata response kept on disk.
+type cachedResponse struct {
+	URL          string          `json:"url"`
+	Fetched      time.Time       `json:"fetched"`
+	ContentType  string          `json:"contentType"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// cachingTransport keeps the metadata of the server on disk, so that
+// the launch does not wait for the server and still works when it
+// can not be reached. The cached metadata is used as it is within the
+// ttl, and revalidated with its ETag or Last-Modified after it. It is
+// also used, however old, if the server fails.
+type cachingTransport struct {
+	next http.RoundTripper
+	dir  string
+	ttl  time.Duration
+}
+
+// newCachingTransport returns the caching transport in the cache
+// directory of the user, or nil if there is none.
+func newCachingTransport(next http.RoundTripper, ttl time.Duration) *cachingTransport {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	return &cachingTransport{next: next, dir: filepath.Join(dir, "startspring", "metadata"), ttl: ttl}
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || !strings.HasSuffix(req.URL.Path, "/metadata/client") {
+		return t.next.RoundTrip(req)
+	}
+
+	path := t.path(req)
+	cached := t.read(path)
+	if cached != nil && time.Since(cached.Fetched) < t.ttl {
+		return cached.response(req, false), nil
+	}
+
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		if cached != nil {
+			return cached.response(req, true), nil
+		}
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		cached.Fetched = time.Now()
+		t.write(path, cached)
+		return cached.response(req, false), nil
+	case resp.StatusCode == http.StatusOK:
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		t.write(path, &cachedResponse{
+			URL:          req.URL.String(),
+			Fetched:      time.Now(),
+			ContentType:  resp.Header.Get("Content-Type"),
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Body:         body,
+		})
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	case resp.StatusCode >= 500 && cached != nil:
+		resp.Body.Close()
+		return cached.response(req, true), nil
+	}
+	return resp, nil
+}
+
+// path returns the cache file of the request. The metadata depends
+// on the negotiated format and language too.
+func (t *cachingTransport) path(req *http.Request) string {
+	key := req.URL.String() + "\n" + req.Header.Get("Accept") + "\n" + req.Header.Get("Accept-Language")
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(t.dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// read reads the cached response. A missing or broken file is not
+// cached.
+func (t *cachingTransport) read(path string) *cachedResponse {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(b, &cached); err != nil || len(cached.Body) == 0 {
+		return nil
+	}
+	return &cached
+}
+
+// write writes the cached response. Failures are ignored since the
+// metadata is fetched again next time.
+func (t *cachingTransport) write(path string, cached *cachedResponse) {
+	b, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return
+	}
+	os.WriteFile(path, b, 0644)
+}
+
+// staleWarning is the Warning header of a response which is served
+// from the cache because the server failed.
+const staleWarning = `110 - "Response is Stale"`
+
+func (c *cachedResponse) response(req *http.Request, stale bool) *http.Response {
+	header := make(http.Header)
+	header.Set("Content-Type", c.ContentType)
+	if stale {
+		header.Set("Warning", staleWarning)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,112 @@
+package wizard
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// catalogPrefix marks the ids of the catalog projects among the
+// dependencies of the picker. They are applied after generation
+// instead of being sent to the server.
+const catalogPrefix = "catalog:"
+
+// catalogProject is a project of a Spring CLI project catalog which
+// can be applied onto a generated project with spring boot add.
+type catalogProject struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	URL         string   `yaml:"url"`
+	Tags        []string `yaml:"tags"`
+}
+
+// readCatalog reads the projects of a Spring CLI catalog from its
+// project-catalog.yaml, given as a file or an http url.
+func readCatalog(client *http.Client, src string) ([]catalogProject, error) {
+	var b []byte
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		resp, err := client.Get(src)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch catalog %s: %s", src, resp.Status)
+		}
+		if b, err = io.ReadAll(resp.Body); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if b, err = os.ReadFile(src); err != nil {
+			return nil, err
+		}
+	}
+
+	var catalog struct {
+		ProjectRepositories []catalogProject `yaml:"projectRepositories"`
+	}
+	if err := yaml.NewDecoder(bytes.NewReader(b)).Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("invalid catalog %s: %w", src, err)
+	}
+	for _, p := range catalog.ProjectRepositories {
+		if p.Name == "" || p.URL == "" {
+			return nil, fmt.Errorf("invalid catalog %s: every project needs a name and a url", src)
+		}
+	}
+	return catalog.ProjectRepositories, nil
+}
+
+// splitCatalog separates the selected catalog projects from the
+// dependencies.
+func splitCatalog(ids []string, catalog []catalogProject) (deps []string, projects []catalogProject) {
+	for _, id := range ids {
+		if !strings.HasPrefix(id, catalogPrefix) {
+			deps = append(deps, id)
+			continue
+		}
+		for _, p := range catalog {
+			if catalogPrefix+p.Name == id {
+				projects = append(projects, p)
+			}
+		}
+	}
+	return deps, projects
+}
+
+// applyCatalog applies the catalog projects onto the project in the
+// directory with the Spring CLI. It returns a warning for each
+// project which could not be applied.
+func applyCatalog(dir string, projects []catalogProject) []string {
+	if len(projects) == 0 {
+		return nil
+	}
+	if _, err := exec.LookPath("spring"); err != nil {
+		var warnings []string
+		for _, p := range projects {
+			warnings = append(warnings, fmt.Sprintf(
+				"No Spring CLI found, apply %s with: spring boot add %s", p.Name, p.URL))
+		}
+		return warnings
+	}
+
+	var warnings []string
+	for _, p := range projects {
+		cmd := exec.Command("spring", "boot", "add", p.URL)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			msg := strings.TrimSpace(string(out))
+			if msg == "" {
+				msg = err.Error()
+			}
+			warnings = append(warnings, fmt.Sprintf("Failed to apply %s: %s", p.Name, msg))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,316 @@
+package wizard
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Main runs the startspring command with the arguments of the
+// process. It exits the process on errors.
+func Main() {
+	defer closeTraces()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "add":
+			runAdd(os.Args[2:])
+			return
+		case "advise":
+			runAdvise(os.Args[2:])
+			return
+		case "buildfile":
+			runBuildFile(os.Args[2:])
+			return
+		case "doctor":
+			runDoctor(os.Args[2:])
+			return
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "regenerate":
+			runRegenerate(os.Args[2:])
+			return
+		case "verify":
+			runVerify(os.Args[2:])
+			return
+		case "generate":
+			runGenerate(os.Args[2:])
+			return
+		case "jhipster":
+			runJHipster(os.Args[2:])
+			return
+		case "workspace":
+			runWorkspace(os.Args[2:])
+			return
+		case "mock-server":
+			runMockServer(os.Args[2:])
+			return
+		}
+	}
+
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: startspring [flags]")
+		fmt.Fprintln(flag.CommandLine.Output(),
+			"The form is skipped if a project flag other than -package-name and -base-dir is given.")
+		flag.PrintDefaults()
+	}
+
+	var opts options
+	var resume, profile, saveAs string
+	var catalogs listFlag
+	info := &projectInfo{}
+	flag.BoolVar(&opts.web, "web", false,
+		"open the selections in start.spring.io instead of generating the project")
+	flag.BoolVar(&opts.share, "share", false,
+		"print the start.spring.io link and the curl command of the project instead of generating it")
+	flag.BoolVar(&opts.qr, "qr", false,
+		"show a QR code of the start.spring.io url after completion")
+	flag.BoolVar(&opts.plain, "plain", false,
+		"use a compact and colorless UI for limited terminals")
+	flag.BoolVar(&opts.accessible, "accessible", os.Getenv("ACCESSIBLE") != "",
+		"ask the questions one per line for screen readers and dumb terminals, also set by ACCESSIBLE")
+	flag.BoolVar(&opts.accessible, "no-tui", os.Getenv("ACCESSIBLE") != "",
+		"same as -accessible")
+	flag.BoolVar(&opts.explore, "explore", false,
+		"browse the files of the project before extracting it")
+	flag.BoolVar(&opts.reproducible, "reproducible", false,
+		"normalize the timestamps and the permissions of the extracted files")
+	flag.BoolVar(&opts.lint, "lint", false,
+		"check the generated build file for duplicate dependencies, conflicting starters and missing annotation processors")
+	projectFlags(flag.CommandLine, info)
+	noInput := flag.Bool("no-input", false,
+		"generate the project from the flags and the server defaults without the form")
+	flag.BoolVar(&opts.offline, "offline", false,
+		"generate a barebones project from the bundled templates without a server")
+	flag.StringVar(&info.output, "output", "",
+		"`directory` to extract the project into, the base directory in the current directory if empty")
+	flag.StringVar(&opts.existing, "existing", existingFail,
+		"what to do if the output directory is not empty: fail, overwrite or merge")
+	flag.StringVar(&opts.eol, "eol", eolKeep,
+		"line endings of the extracted text files: keep, lf or crlf")
+	flag.Var((*listFlag)(&info.addOns), "add-ons",
+		"comma separated add-ons to apply after generation, e.g. dotenv")
+	flag.Var(&catalogs, "catalog",
+		"project-catalog.yaml `file or url` of a Spring CLI catalog whose projects can be applied")
+	flag.StringVar(&resume, "resume", "",
+		"continue with the answers saved in the session `file` when quitting")
+	flag.StringVar(&profile, "profile", "",
+		"start with the answers of the saved profile")
+	flag.StringVar(&saveAs, "save-profile", "",
+		"save the answers as a profile with this `name` once the form is completed")
+	flag.StringVar(&opts.jsonOut, "json", "",
+		"write a json summary of the generated project to this `file`, - for stdout")
+	flag.StringVar(&opts.push, "push", "",
+		"create a repository on github or gitlab and push the project to it, with the token of GITHUB_TOKEN or GITLAB_TOKEN")
+	flag.StringVar(&opts.repo, "repo", "",
+		"`name` or owner/name of the repository created by -push, the artifact id if empty")
+	flag.BoolVar(&opts.public, "public", false,
+		"make the repository created by -push public instead of private")
+	flag.StringVar(&opts.open, "open", "",
+		"open the generated project in this `editor`: code, idea, editor for $EDITOR, one of editors.yaml or auto; list shows the detected editors")
+	httpFlags(flag.CommandLine, &opts.http)
+	flag.Parse()
+
+	// The package name and the base directory only fill in the form,
+	// any other project flag describes the project to generate.
+	described := false
+	project := projectFlagNames()
+	flag.Visit(func(f *flag.Flag) {
+		if project[f.Name] && f.Name != "package-name" && f.Name != "base-dir" {
+			described = true
+		}
+	})
+	headless := *noInput || described
+
+	switch opts.eol {
+	case eolKeep, eolLF, eolCRLF:
+	default:
+		die(fmt.Errorf("invalid -eol '%s', should be keep, lf or crlf", opts.eol))
+	}
+	switch opts.existing {
+	case existingFail, existingOverwrite, existingMerge:
+	default:
+		die(fmt.Errorf("invalid -existing '%s', should be fail, overwrite or merge", opts.existing))
+	}
+	if opts.push != "" {
+		if err := checkPush(opts.push); err != nil {
+			die(err)
+		}
+	}
+	if !headless || opts.open != "" {
+		editors, err := readEditors()
+		if err != nil {
+			die(err)
+		}
+		opts.editors = editors
+	}
+	if opts.open == "list" {
+		listEditors(opts.editors)
+		return
+	}
+	if opts.open != "" {
+		if _, err := findEditor(opts.open, opts.editors); err != nil {
+			die(err)
+		}
+	}
+
+	if resume != "" {
+		if described {
+			die(errors.New("-resume can not be combined with the project flags, use -no-input to generate the saved session"))
+		}
+		saved, err := readConfig(resume)
+		if err != nil {
+			die(err)
+		}
+		// The flags take precedence over the saved answers.
+		if info.packageName != "" {
+			saved.packageName = info.packageName
+		}
+		if info.baseDir != "" {
+			saved.baseDir = info.baseDir
+		}
+		if len(info.addOns) > 0 {
+			saved.addOns = info.addOns
+		}
+		saved.output = info.output
+		info = saved
+	}
+
+	// The profile fills in the answers which are not given, or they
+	// are asked for it in the form.
+	if profile != "" {
+		saved, err := loadProfile(profile)
+		if err != nil {
+			die(err)
+		}
+		info.fill(saved)
+	} else if !headless {
+		profiles, err := readProfiles()
+		if err != nil {
+			die(err)
+		}
+		opts.profiles = profiles
+	}
+	if !headless {
+		bundles, err := readBundles()
+		if err != nil {
+			die(err)
+		}
+		opts.bundles = bundles
+	}
+
+	// The prompts of the accessible mode are not styled either.
+	if opts.accessible {
+		opts.plain = true
+	}
+	if opts.plain {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	client, err := newClient(opts.http)
+	if err != nil {
+		die(err)
+	}
+
+	for _, src := range catalogs {
+		projects, err := readCatalog(client.HTTPClient, src)
+		if err != nil {
+			die(err)
+		}
+		opts.catalog = append(opts.catalog, projects...)
+	}
+
+	if opts.offline {
+		client = newOfflineClient(client.URL)
+	}
+
+	data, err := fetchMetadata(client)
+	if err != nil && !opts.offline {
+		// Fall back to the bundled templates rather than leaving the
+		// user without a project.
+		fmt.Fprintf(os.Stderr, "%s\n", warnStyle.Render(fmt.Sprintf(
+			"Could not reach %s (%v), using the bundled templates", client.URL, err)))
+		opts.offline = true
+		client = newOfflineClient(client.URL)
+		data, err = fetchMetadata(client)
+	}
+	if err != nil {
+		die(err)
+	}
+	if data.Stale {
+		fmt.Fprintf(os.Stderr, "%s\n", warnStyle.Render(fmt.Sprintf(
+			"Could not reach %s, using the cached metadata", client.URL)))
+	}
+
+	if headless {
+		runHeadless(data, client, info, opts)
+		saveAsProfile(saveAs, info)
+		return
+	}
+
+	rememberAnswers(data)
+	if opts.accessible {
+		runAccessible(data, client, info, opts)
+		saveAsProfile(saveAs, info)
+		return
+	}
+	program := tea.NewProgram(newModel(data, client, info, opts))
+	final, err := program.Run()
+	// A download which is still running when the UI is quit leaves
+	// its archive.
+	removeTempArchives()
+	if err != nil {
+		die(err)
+	}
+	if m, ok := final.(model); ok && m.form.State == huh.StateCompleted {
+		saveAsProfile(saveAs, m.info)
+	}
+	// Only the answers of a generated project are remembered.
+	if m, ok := final.(model); ok && m.projectDir != "" && m.err == nil {
+		if err := saveLastAnswers(m.info); err != nil {
+			fmt.Fprintln(os.Stderr, warnStyle.Render("Failed to remember the answers: "+err.Error()))
+		}
+		if opts.jsonOut != "" {
+			files := m.files
+			if m.buildFile != "" {
+				files = []string{filepath.Base(m.buildFile)}
+			}
+			if err := writeResult(opts.jsonOut, m.info, m.projectDir, files); err != nil {
+				die(err)
+			}
+		}
+	}
+}
+
+// saveAsProfile saves the answers as the profile if a name is given.
+func saveAsProfile(name string, info *projectInfo) {
+	if name == "" {
+		return
+	}
+	if err := saveProfile(name, info); err != nil {
+		die(err)
+	}
+	// The message is kept out of the json summary on stdout.
+	fmt.Fprintf(os.Stderr, "Saved the answers as the profile %s\n", name)
+}
+
+func die(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	exit(1)
+}
+
+// exit exits with the code. The archives which are in use are
+// removed and the trace files are closed first, as os.Exit skips the
+// deferred calls which would do it.
+func exit(code int) {
+	removeTempArchives()
+	closeTraces()
+	os.Exit(code)
+}
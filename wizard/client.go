@@ -0,0 +1,413 @@
+package wizard
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// httpOptions holds the options of the http client.
+type httpOptions struct {
+	traceFile   string
+	traceBodies bool
+	// language is the preferred language of the responses, e.g.
+	// de-DE. The locale of the user is used if it is empty.
+	language string
+	// server is the url of the Initializr server.
+	server string
+	// cacheTTL is how long the cached metadata is used without
+	// asking the server. The metadata is not cached if it is
+	// negative.
+	cacheTTL time.Duration
+	// timeout is the time limit to connect to the server and to
+	// receive the headers of a response. The body of the response is
+	// not limited, as a large archive takes long on a slow network.
+	// There is none if it is 0.
+	timeout time.Duration
+	// retries is how many times a request which fails because of the
+	// network or a gateway is retried.
+	retries int
+	// caCert is a file of PEM certificates which are trusted besides
+	// the ones of the system, e.g. the CA of a corporate proxy.
+	caCert   string
+	insecure bool
+}
+
+// httpFlags defines the flags of the http client in the flag set.
+func httpFlags(fs *flag.FlagSet, opts *httpOptions) {
+	fs.StringVar(&opts.traceFile, "trace-http", "",
+		"dump the headers of the http requests and responses to this file")
+	fs.BoolVar(&opts.traceBodies, "trace-bodies", false,
+		"dump the bodies too with -trace-http")
+	fs.StringVar(&opts.language, "lang", "",
+		"preferred language of the dependency names and descriptions, e.g. de-DE, taken from the locale if empty")
+	server := os.Getenv("STARTSPRING_URL")
+	if server == "" {
+		server = defaultServerURL
+	}
+	fs.StringVar(&opts.server, "server", server,
+		"`url` of the Spring Initializr server, defaults to STARTSPRING_URL or start.spring.io")
+	fs.DurationVar(&opts.cacheTTL, "cache-ttl", defaultCacheTTL,
+		"how long the cached metadata of the server is used before it is revalidated, 0 to always revalidate")
+	fs.DurationVar(&opts.timeout, "timeout", defaultTimeout,
+		"time limit to connect to the server and receive the headers of a response, 0 for none")
+	fs.IntVar(&opts.retries, "retries", defaultRetries,
+		"how many times a request is retried if the server or the network fails")
+	fs.StringVar(&opts.caCert, "ca-cert", "",
+		"PEM `file` of the certificates to trust besides the ones of the system, e.g. of a corporate proxy")
+	fs.BoolVar(&opts.insecure, "insecure", false,
+		"do not verify the certificate of the server")
+}
+
+const (
+	defaultTimeout = time.Minute
+	defaultRetries = 3
+	// retryBackoff is the wait before the first retry, which doubles
+	// with every retry.
+	retryBackoff = 500 * time.Millisecond
+)
+
+// newClient returns the client of the server of the options, which
+// is start.spring.io if it is empty.
+func newClient(opts httpOptions) (*initializr.Client, error) {
+	server, err := parseServer(opts.server)
+	if err != nil {
+		return nil, err
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.caCert != "" || opts.insecure {
+		config, err := tlsConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+		t.TLSClientConfig = config
+	}
+	// Only waiting for the server is limited, not the download of
+	// the body.
+	if opts.timeout > 0 {
+		dialer := &net.Dialer{Timeout: opts.timeout, KeepAlive: 30 * time.Second}
+		t.DialContext = dialer.DialContext
+		t.TLSHandshakeTimeout = opts.timeout
+		t.ResponseHeaderTimeout = opts.timeout
+	}
+	var transport http.RoundTripper = t
+	if opts.traceFile != "" {
+		f, err := openTrace(opts.traceFile)
+		if err != nil {
+			return nil, err
+		}
+		transport = &tracingTransport{
+			next:   transport,
+			w:      f,
+			bodies: opts.traceBodies,
+		}
+	}
+
+	if opts.retries > 0 {
+		transport = &retryTransport{next: transport, retries: opts.retries, backoff: retryBackoff}
+	}
+
+	if opts.cacheTTL >= 0 {
+		if t := newCachingTransport(transport, opts.cacheTTL); t != nil {
+			transport = t
+		}
+	}
+
+	lang := opts.language
+	if lang == "" {
+		lang = locale()
+	}
+	if lang != "" {
+		transport = &languageTransport{next: transport, language: acceptLanguage(lang)}
+	}
+	return &initializr.Client{HTTPClient: &http.Client{Transport: transport}, URL: server}, nil
+}
+
+// tlsConfig returns the TLS config which trusts the certificates of
+// the -ca-cert file, or none with -insecure.
+func tlsConfig(opts httpOptions) (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: opts.insecure}
+	if opts.caCert == "" {
+		return config, nil
+	}
+	pem, err := os.ReadFile(opts.caCert)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", opts.caCert)
+	}
+	config.RootCAs = pool
+	return config, nil
+}
+
+// parseServer checks the url of the server and returns it without a
+// trailing slash, or start.spring.io if it is empty. The url may
+// have a path if the server is not at the root, e.g.
+// https://example.com/initializr.
+func parseServer(s string) (string, error) {
+	if s == "" {
+		return defaultServerURL, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid server url '%s', should be like https://start.spring.io", s)
+	}
+	return strings.TrimSuffix(s, "/"), nil
+}
+
+// languageTransport asks for the responses in the language of the
+// user, so that the servers which localize the metadata return it in
+// that language.
+type languageTransport struct {
+	next     http.RoundTripper
+	language string
+}
+
+func (t *languageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Language") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Language", t.language)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// locale returns the language of the user from the environment as a
+// language tag, e.g. de-DE for LANG=de_DE.UTF-8. It is empty for the
+// C locale.
+func locale() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		v, _, _ = strings.Cut(v, ".")
+		v, _, _ = strings.Cut(v, "@")
+		if v == "C" || v == "POSIX" {
+			return ""
+		}
+		return strings.ReplaceAll(v, "_", "-")
+	}
+	return ""
+}
+
+// acceptLanguage returns the Accept-Language header of the language
+// tag which falls back to the language without the region, e.g.
+// de-DE,de;q=0.9.
+func acceptLanguage(lang string) string {
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		return lang + "," + base + ";q=0.9"
+	}
+	return lang
+}
+
+// retryTransport retries the requests which fail because of the
+// network or a gateway of the server, like the 502s of start.spring.io
+// while it is redeployed. The wait before a retry doubles every time.
+// Only the requests which can be sent again are retried.
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	wait := t.backoff
+	retry := isIdempotent(req)
+	for i := 0; ; i++ {
+		attempt := req
+		if i > 0 && req.GetBody != nil {
+			// The body was read by the previous attempt.
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt = req.Clone(req.Context())
+			attempt.Body = body
+		}
+		resp, err := t.next.RoundTrip(attempt)
+		if isCertificateError(err) {
+			return nil, fmt.Errorf("%w (trust the CA of your proxy with -ca-cert, or skip the check with -insecure)", err)
+		}
+		if i == t.retries || !retry || !isRetryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// isIdempotent reports whether the request can be sent again. The
+// form posts which generate a project are, as the server generates
+// the same project every time, unlike e.g. the posts which create a
+// repository on a git host.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost:
+		return (req.Body == nil || req.GetBody != nil) &&
+			strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded")
+	}
+	return false
+}
+
+// isRetryable reports whether the request may succeed if it is sent
+// again.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		// An unknown host does not come back within the retries.
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false
+		}
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isCertificateError reports whether the certificate of the server
+// is not trusted, as with a proxy which intercepts TLS.
+func isCertificateError(err error) bool {
+	var unknown x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	return errors.As(err, &unknown) || errors.As(err, &hostname) || errors.As(err, &invalid)
+}
+
+// tracingTransport dumps the requests and the responses which
+// pass through it for debugging proxies and content negotiation.
+type tracingTransport struct {
+	next   http.RoundTripper
+	bodies bool
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqDump, err := httputil.DumpRequestOut(req, t.bodies)
+	if err != nil {
+		return nil, err
+	}
+	// The tokens of the git hosts are not written to the trace.
+	for _, h := range []string{"Authorization", "Private-Token"} {
+		if v := req.Header.Get(h); v != "" {
+			reqDump = bytes.ReplaceAll(reqDump, []byte(v), []byte("REDACTED"))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fmt.Fprintf(t.w, "=== %s %s %s\n", start.Format(time.RFC3339), req.Method, req.URL)
+	t.w.Write(reqDump)
+	if err != nil {
+		fmt.Fprintf(t.w, "\n--- error after %s: %v\n\n", elapsed, err)
+		return nil, err
+	}
+
+	// Archives are not readable, so only their headers are dumped.
+	body := t.bodies && !isBinary(resp.Header.Get("Content-Type"))
+	respDump, err := httputil.DumpResponse(resp, body)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(t.w, "\n--- response after %s\n", elapsed)
+	t.w.Write(respDump)
+	fmt.Fprint(t.w, "\n\n")
+	return resp, nil
+}
+
+// traceFile is a file of -trace-http. A failed write does not fail
+// the request, so the first error is kept and reported when the file
+// is closed.
+type traceFile struct {
+	f   *os.File
+	err error
+}
+
+// traceFiles are the open trace files, which closeTraces closes
+// when the program exits.
+var traceFiles = struct {
+	sync.Mutex
+	files []*traceFile
+}{}
+
+func openTrace(name string) (*traceFile, error) {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	t := &traceFile{f: f}
+	traceFiles.Lock()
+	traceFiles.files = append(traceFiles.files, t)
+	traceFiles.Unlock()
+	return t, nil
+}
+
+func (t *traceFile) Write(p []byte) (int, error) {
+	n, err := t.f.Write(p)
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+	return n, err
+}
+
+// closeTraces closes the trace files and reports the ones which
+// could not be written.
+func closeTraces() {
+	traceFiles.Lock()
+	defer traceFiles.Unlock()
+	for _, t := range traceFiles.files {
+		err := t.f.Close()
+		if t.err != nil {
+			err = t.err
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write the http trace to %s: %v\n", t.f.Name(), err)
+		}
+	}
+	traceFiles.files = nil
+}
+
+func isBinary(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/zip") ||
+		strings.HasPrefix(contentType, "application/octet-stream") ||
+		strings.HasPrefix(contentType, "application/x-compress")
+}
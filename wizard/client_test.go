@@ -0,0 +1,54 @@
+package wizard
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRetryTransportRetriesFormPost(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if err := r.ParseForm(); err != nil || r.Form.Get("name") != "demo" {
+			t.Errorf("attempt %d: got form %v, %v", attempts, r.Form, err)
+		}
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		io.WriteString(w, "zip")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport, retries: 2}}
+	resp, err := client.PostForm(srv.URL, url.Values{"name": {"demo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || attempts != 2 {
+		t.Fatalf("got %s after %d attempts, want 200 OK after 2", resp.Status, attempts)
+	}
+}
+
+func TestRetryTransportDoesNotRetryJSONPost(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport, retries: 2}}
+	resp, err := client.Post(srv.URL, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if attempts != 1 {
+		t.Fatalf("a json post was sent %d times, want once", attempts)
+	}
+}
@@ -0,0 +1,58 @@
+package wizard
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// copyToClipboard copies the text to the system clipboard. If there
+// is no native clipboard (e.g. inside an ssh session), the text is
+// sent to the terminal with an OSC52 escape sequence instead.
+func copyToClipboard(text string) error {
+	if err := clipboard.WriteAll(text); err == nil {
+		return nil
+	}
+
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
+
+// runCommand returns the command to change directory to the
+// project and run it with the build tool wrapper.
+func runCommand(dir, projectType string) string {
+	var run string
+	if strings.HasPrefix(projectType, "gradle") {
+		run = "gradlew bootRun"
+		if runtime.GOOS == "windows" {
+			run = "gradlew.bat bootRun"
+		}
+	} else {
+		run = "mvnw spring-boot:run"
+		if runtime.GOOS == "windows" {
+			run = "mvnw.cmd spring-boot:run"
+		}
+	}
+	if runtime.GOOS != "windows" {
+		run = "./" + run
+	}
+	return "cd " + quotePath(dir) + " && " + run
+}
+
+// quotePath quotes the path if it contains space.
+func quotePath(path string) string {
+	if strings.ContainsAny(path, " \t") {
+		return `"` + path + `"`
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,120 @@
+package wizard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// config is a project configuration file in yaml, e.g.
+//
+//	name: demo
+//	bootVersion: 3.3.4
+//	dependencies: [web, actuator]
+//
+// The keys are the parameters of start.spring.io and the fields of
+// a custom server are set with params.
+type config struct {
+	Name         string            `yaml:"name,omitempty"`
+	GroupId      string            `yaml:"groupId,omitempty"`
+	ArtifactId   string            `yaml:"artifactId,omitempty"`
+	Version      string            `yaml:"version,omitempty"`
+	Description  string            `yaml:"description,omitempty"`
+	PackageName  string            `yaml:"packageName,omitempty"`
+	BaseDir      string            `yaml:"baseDir,omitempty"`
+	Type         string            `yaml:"type,omitempty"`
+	Language     string            `yaml:"language,omitempty"`
+	BootVersion  string            `yaml:"bootVersion,omitempty"`
+	Packaging    string            `yaml:"packaging,omitempty"`
+	JavaVersion  string            `yaml:"javaVersion,omitempty"`
+	Dependencies []string          `yaml:"dependencies,omitempty"`
+	AddOns       []string          `yaml:"addOns,omitempty"`
+	Params       map[string]string `yaml:"params,omitempty"`
+}
+
+// sessionFile is the configuration file where the answers of an
+// unfinished form are saved.
+const sessionFile = "startspring-session.yaml"
+
+// readConfig reads the project from the configuration file.
+func readConfig(path string) (*projectInfo, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg config
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	// Misspelled keys would silently fall back to the defaults.
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg.projectInfo(), nil
+}
+
+// writeConfig writes the project to the configuration file. The
+// empty values are left out so that the defaults are used when the
+// file is read.
+func writeConfig(path string, info *projectInfo) error {
+	cfg := newConfig(info)
+	b, err := yaml.Marshal(&cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// newConfig returns the configuration of the project without the
+// empty params.
+func newConfig(info *projectInfo) config {
+	cfg := config{
+		Name:         info.name,
+		GroupId:      info.group,
+		ArtifactId:   info.artifact,
+		Version:      info.version,
+		Description:  info.description,
+		PackageName:  info.packageName,
+		BaseDir:      info.baseDir,
+		Type:         info.projectType,
+		Language:     info.language,
+		BootVersion:  info.bootVersion,
+		Packaging:    info.packaging,
+		JavaVersion:  info.javaVersion,
+		Dependencies: info.dependencies,
+		AddOns:       info.addOns,
+	}
+	for k, v := range info.extra {
+		if *v == "" {
+			continue
+		}
+		if cfg.Params == nil {
+			cfg.Params = make(map[string]string)
+		}
+		cfg.Params[k] = *v
+	}
+	return cfg
+}
+
+func (cfg config) projectInfo() *projectInfo {
+	return newProjectInfo(Project{
+		Name:         cfg.Name,
+		Group:        cfg.GroupId,
+		Artifact:     cfg.ArtifactId,
+		Version:      cfg.Version,
+		Description:  cfg.Description,
+		PackageName:  cfg.PackageName,
+		BaseDir:      cfg.BaseDir,
+		Type:         cfg.Type,
+		Language:     cfg.Language,
+		BootVersion:  cfg.BootVersion,
+		Packaging:    cfg.Packaging,
+		JavaVersion:  cfg.JavaVersion,
+		Dependencies: cfg.Dependencies,
+		AddOns:       cfg.AddOns,
+		Params:       cfg.Params,
+	})
+}
@@ -0,0 +1,726 @@
+package wizard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/accessibility"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// depOption is a dependency which can be picked.
+type depOption struct {
+	id    string
+	label string
+	// note is shown after the label, e.g. the boot versions which
+	// the dependency is compatible with.
+	note string
+	// group is the category of the dependency, e.g. Web.
+	group string
+	// description is shown below the list while the dependency is
+	// highlighted.
+	description string
+}
+
+// depRow is a row of the list of the picker. It is either the
+// header of a category or a dependency of it.
+type depRow struct {
+	group  string
+	option *depOption
+}
+
+func (r depRow) isHeader() bool {
+	return r.option == nil
+}
+
+// depPicker is the multi-select field of the dependencies. Unlike
+// huh.MultiSelect, it keeps its value up to date on every toggle so
+// that the selection can be used before the field is left.
+type depPicker struct {
+	value       *[]string
+	title       string
+	description string
+	height      int
+	width       int
+
+	options  []depOption
+	filtered []depOption
+	rows     []depRow
+	cursor   int
+	offset   int
+
+	// collapsed are the categories whose dependencies are hidden.
+	// It is kept when the options are replaced.
+	collapsed map[string]bool
+
+	// build builds the options for the boot version and the facets,
+	// which are the answers of the form the options depend on. They
+	// are rebuilt by refilter when the answers change.
+	build   func(bootVersion string, facets []string) []depOption
+	facets  []string
+	filters string
+
+	// coordinates are the maven coordinates of the dependencies
+	// which the search matches besides their ids, names,
+	// categories and descriptions.
+	coordinates map[string]string
+
+	// bundles are the names of the selected dependency bundles and
+	// applied the ones whose dependencies are selected.
+	bundles []string
+	applied []string
+
+	filtering bool
+	filter    textinput.Model
+
+	// accessible asks the dependencies as a line of ids instead of
+	// showing the list. prepare is called before, as the form does
+	// not update the options between the fields in accessible mode.
+	accessible bool
+	prepare    func()
+
+	focused bool
+	theme   *huh.Theme
+	keymap  huh.MultiSelectKeyMap
+	keys    depKeyMap
+}
+
+// depKeyMap are the keys of the picker in addition to the ones of
+// huh.MultiSelect.
+type depKeyMap struct {
+	Collapse    key.Binding
+	Expand      key.Binding
+	CollapseAll key.Binding
+	ExpandAll   key.Binding
+}
+
+var defaultDepKeyMap = depKeyMap{
+	Collapse:    key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/→", "collapse/expand")),
+	Expand:      key.NewBinding(key.WithKeys("right", "l")),
+	CollapseAll: key.NewBinding(key.WithKeys("-"), key.WithHelp("-/+", "collapse/expand all")),
+	ExpandAll:   key.NewBinding(key.WithKeys("+", "=")),
+}
+
+func newDepPicker(value *[]string, height int) *depPicker {
+	filter := textinput.New()
+	filter.Prompt = "/"
+	return &depPicker{
+		value:     value,
+		height:    height,
+		filter:    filter,
+		collapsed: make(map[string]bool),
+		keys:      defaultDepKeyMap,
+		theme:     huh.ThemeCharm(),
+		keymap:    huh.NewDefaultKeyMap().MultiSelect,
+	}
+}
+
+// setOptions replaces the dependencies which can be picked. The
+// selected dependencies which are not in the options any more are
+// unselected.
+func (p *depPicker) setOptions(options []depOption) {
+	p.options = options
+
+	available := make(map[string]bool)
+	for _, o := range options {
+		available[o.id] = true
+	}
+	var value []string
+	for _, id := range *p.value {
+		if available[id] {
+			value = append(value, id)
+		}
+	}
+	*p.value = value
+	p.applyFilter()
+}
+
+// refilter rebuilds the options if the boot version or the facets
+// changed since they were built. It returns the names of the selected
+// dependencies which are not available any more and so unselected.
+func (p *depPicker) refilter(bootVersion string) []string {
+	filters := bootVersion + "|" + strings.Join(p.facets, ",")
+	if p.build == nil || filters == p.filters {
+		return nil
+	}
+	p.filters = filters
+
+	names := make(map[string]string)
+	for _, o := range p.options {
+		names[o.id] = o.label
+	}
+	selected := append([]string(nil), *p.value...)
+	p.setOptions(p.build(bootVersion, p.facets))
+
+	var removed []string
+	for _, id := range selected {
+		if p.isSelected(id) {
+			continue
+		}
+		if name, ok := names[id]; ok {
+			removed = append(removed, name)
+		} else {
+			removed = append(removed, id)
+		}
+	}
+	return removed
+}
+
+// applyBundles selects the dependencies of the bundles which were
+// selected since it was last called, and unselects the ones of the
+// bundles which were unselected unless another selected bundle has
+// them. It returns the dependencies of the selected bundles which
+// can not be picked.
+func (p *depPicker) applyBundles(bundles map[string][]string) []string {
+	if strings.Join(p.bundles, ",") == strings.Join(p.applied, ",") {
+		return nil
+	}
+	selected := make(map[string]bool)
+	kept := make(map[string]bool)
+	for _, name := range p.bundles {
+		selected[name] = true
+		for _, id := range bundles[name] {
+			kept[id] = true
+		}
+	}
+	wasApplied := make(map[string]bool)
+	for _, name := range p.applied {
+		wasApplied[name] = true
+		if selected[name] {
+			continue
+		}
+		for _, id := range bundles[name] {
+			if !kept[id] && p.isSelected(id) {
+				p.toggle(id)
+			}
+		}
+	}
+
+	available := make(map[string]bool)
+	for _, o := range p.options {
+		available[o.id] = true
+	}
+	var missing []string
+	for _, name := range p.bundles {
+		if wasApplied[name] {
+			continue
+		}
+		for _, id := range bundles[name] {
+			switch {
+			case !available[id]:
+				missing = append(missing, id)
+			case !p.isSelected(id):
+				p.toggle(id)
+			}
+		}
+	}
+	p.applied = append([]string(nil), p.bundles...)
+	return missing
+}
+
+func (p *depPicker) isSelected(id string) bool {
+	for _, v := range *p.value {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *depPicker) toggle(id string) {
+	for i, v := range *p.value {
+		if v == id {
+			*p.value = append((*p.value)[:i:i], (*p.value)[i+1:]...)
+			return
+		}
+	}
+	*p.value = append(*p.value, id)
+}
+
+// applyFilter filters the options by the text of the filter and
+// lays out the rows of the categories. The cursor stays on the same
+// row if it is still shown.
+func (p *depPicker) applyFilter() {
+	var current depRow
+	if p.cursor < len(p.rows) {
+		current = p.rows[p.cursor]
+	}
+
+	query := strings.TrimSpace(p.filter.Value())
+	p.filtered = nil
+	if query == "" {
+		p.filtered = append(p.filtered, p.options...)
+	} else {
+		scores := make(map[string]int)
+		for _, o := range p.options {
+			if score := matchScore(query, o, p.coordinates[o.id]); score > 0 {
+				scores[o.id] = score
+				p.filtered = append(p.filtered, o)
+			}
+		}
+		sortMatches(p.filtered, scores)
+	}
+
+	p.rows = nil
+	for i := range p.filtered {
+		o := &p.filtered[i]
+		if i == 0 || o.group != p.filtered[i-1].group {
+			p.rows = append(p.rows, depRow{group: o.group})
+		}
+		// The matches of the filter are shown even in the
+		// collapsed categories.
+		if !p.collapsed[o.group] || p.filterActive() {
+			p.rows = append(p.rows, depRow{group: o.group, option: o})
+		}
+	}
+
+	for i, r := range p.rows {
+		if r.group == current.group && (r.option == nil) == (current.option == nil) &&
+			(r.option == nil || r.option.id == current.option.id) {
+			p.cursor = i
+			break
+		}
+	}
+	if p.cursor >= len(p.rows) {
+		p.cursor = len(p.rows) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+	p.scroll()
+}
+
+// toggleCollapse collapses or expands the category and moves the
+// cursor to its header.
+func (p *depPicker) toggleCollapse(group string) {
+	p.collapsed[group] = !p.collapsed[group]
+	for i, r := range p.rows {
+		if r.group == group && r.isHeader() {
+			p.cursor = i
+			break
+		}
+	}
+}
+
+// collapseAll collapses or expands all the categories.
+func (p *depPicker) collapseAll(collapse bool) {
+	group := ""
+	if p.cursor < len(p.rows) {
+		group = p.rows[p.cursor].group
+	}
+	for _, o := range p.options {
+		p.collapsed[o.group] = collapse
+	}
+	for i, r := range p.rows {
+		if r.group == group && r.isHeader() {
+			p.cursor = i
+			break
+		}
+	}
+}
+
+// selectedIn returns the number of the selected dependencies of the
+// category.
+func (p *depPicker) selectedIn(group string) int {
+	n := 0
+	for _, o := range p.options {
+		if o.group == group && p.isSelected(o.id) {
+			n++
+		}
+	}
+	return n
+}
+
+// filterActive checks if the options are filtered by a text.
+func (p *depPicker) filterActive() bool {
+	return p.filtering || p.filter.Value() != ""
+}
+
+// filterStatus returns the number of the options which match the
+// filter and a hint about the selected options which it hides.
+func (p *depPicker) filterStatus() string {
+	status := fmt.Sprintf("Showing %d of %d dependencies", len(p.filtered), len(p.options))
+
+	shown := make(map[string]bool)
+	for _, o := range p.filtered {
+		shown[o.id] = true
+	}
+	hidden := 0
+	for _, id := range *p.value {
+		if !shown[id] {
+			hidden++
+		}
+	}
+	if hidden > 0 {
+		status += fmt.Sprintf(" • %d selected hidden by the filter", hidden)
+	}
+	return status
+}
+
+// listHeight returns the number of options shown at once.
+func (p *depPicker) listHeight() int {
+	// The title and the description of the highlighted dependency
+	// take a line each.
+	h := p.height - 2
+	if p.description != "" {
+		h--
+	}
+	if p.filterActive() {
+		h--
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// scroll keeps the cursor in view.
+func (p *depPicker) scroll() {
+	h := p.listHeight()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	} else if p.cursor >= p.offset+h {
+		p.offset = p.cursor - h + 1
+	}
+	if max := len(p.rows) - h; p.offset > max {
+		p.offset = max
+	}
+	if p.offset < 0 {
+		p.offset = 0
+	}
+}
+
+func (p *depPicker) setFiltering(filtering bool) {
+	p.filtering = filtering
+	p.keymap.SetFilter.SetEnabled(filtering)
+	p.keymap.Filter.SetEnabled(!filtering)
+	p.keymap.Next.SetEnabled(!filtering)
+	p.keymap.Submit.SetEnabled(!filtering)
+	p.keymap.Prev.SetEnabled(!filtering)
+	p.keymap.ClearFilter.SetEnabled(!filtering && p.filter.Value() != "")
+	p.keys.Collapse.SetEnabled(!filtering)
+	p.keys.Expand.SetEnabled(!filtering)
+	p.keys.CollapseAll.SetEnabled(!filtering)
+	p.keys.ExpandAll.SetEnabled(!filtering)
+}
+
+func (p *depPicker) Init() tea.Cmd {
+	return nil
+}
+
+func (p *depPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+
+	var cmd tea.Cmd
+	if p.filtering {
+		p.filter, cmd = p.filter.Update(msg)
+	}
+
+	switch {
+	case key.Matches(keyMsg, p.keymap.Filter):
+		p.setFiltering(true)
+		return p, p.filter.Focus()
+	case key.Matches(keyMsg, p.keymap.SetFilter):
+		if len(p.filtered) == 0 {
+			p.filter.SetValue("")
+		}
+		p.filter.Blur()
+		p.setFiltering(false)
+	case key.Matches(keyMsg, p.keymap.ClearFilter):
+		p.filter.SetValue("")
+		p.setFiltering(false)
+	case key.Matches(keyMsg, p.keymap.Up):
+		if p.filtering && keyMsg.String() == "k" {
+			break
+		}
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case key.Matches(keyMsg, p.keymap.Down):
+		if p.filtering && keyMsg.String() == "j" {
+			break
+		}
+		if p.cursor < len(p.rows)-1 {
+			p.cursor++
+		}
+	case key.Matches(keyMsg, p.keymap.Toggle):
+		if p.filtering && keyMsg.String() == "x" || len(p.rows) == 0 {
+			break
+		}
+		if r := p.rows[p.cursor]; r.isHeader() {
+			if !p.filterActive() {
+				p.toggleCollapse(r.group)
+			}
+		} else {
+			p.toggle(r.option.id)
+		}
+	case key.Matches(keyMsg, p.keys.Collapse):
+		if len(p.rows) > 0 && !p.filterActive() && !p.collapsed[p.rows[p.cursor].group] {
+			p.toggleCollapse(p.rows[p.cursor].group)
+		}
+	case key.Matches(keyMsg, p.keys.Expand):
+		if len(p.rows) > 0 && !p.filterActive() && p.collapsed[p.rows[p.cursor].group] {
+			p.toggleCollapse(p.rows[p.cursor].group)
+		}
+	case key.Matches(keyMsg, p.keys.CollapseAll):
+		p.collapseAll(true)
+	case key.Matches(keyMsg, p.keys.ExpandAll):
+		p.collapseAll(false)
+	case !p.filtering && keyMsg.Type == tea.KeyRunes && len(keyMsg.Runes) == 1:
+		labels := make([]string, len(p.rows))
+		for i, r := range p.rows {
+			labels[i] = r.group
+			if !r.isHeader() {
+				labels[i] = r.option.label
+			}
+		}
+		if i := jumpIndex(labels, p.cursor, keyMsg.Runes[0]); i >= 0 {
+			p.cursor = i
+		}
+	case key.Matches(keyMsg, p.keymap.Prev):
+		return p, huh.PrevField
+	case key.Matches(keyMsg, p.keymap.Next, p.keymap.Submit):
+		return p, huh.NextField
+	}
+
+	p.applyFilter()
+	return p, cmd
+}
+
+func (p *depPicker) styles() *huh.FieldStyles {
+	if p.focused {
+		return &p.theme.Focused
+	}
+	return &p.theme.Blurred
+}
+
+func (p *depPicker) View() string {
+	styles := p.styles()
+
+	var sb strings.Builder
+	switch {
+	case p.filtering:
+		sb.WriteString(p.filter.View())
+	case p.filter.Value() != "":
+		sb.WriteString(styles.Title.Render(p.title) + styles.Description.Render("/"+p.filter.Value()))
+	default:
+		sb.WriteString(styles.Title.Render(p.title))
+	}
+	sb.WriteString("\n")
+	if p.description != "" {
+		sb.WriteString(styles.Description.Render(p.description) + "\n")
+	}
+
+	selector := styles.MultiSelectSelector.String()
+	indent := strings.Repeat(" ", lipgloss.Width(selector))
+	h := p.listHeight()
+	for i := p.offset; i < p.offset+h; i++ {
+		if i < len(p.rows) {
+			r := p.rows[i]
+			if i == p.cursor {
+				sb.WriteString(selector)
+			} else {
+				sb.WriteString(indent)
+			}
+			if !r.isHeader() {
+				sb.WriteString("  ")
+			}
+			switch {
+			case r.isHeader():
+				sb.WriteString(p.headerView(r.group))
+			case p.isSelected(r.option.id):
+				sb.WriteString(styles.SelectedPrefix.String())
+				sb.WriteString(styles.SelectedOption.Render(r.option.label))
+			default:
+				sb.WriteString(styles.UnselectedPrefix.String())
+				sb.WriteString(styles.UnselectedOption.Render(r.option.label))
+			}
+			if !r.isHeader() && r.option.note != "" {
+				sb.WriteString(" " + styles.Description.Render(r.option.note))
+			}
+		}
+		if i < p.offset+h-1 {
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n" + styles.Description.Render(p.highlighted()))
+	if p.filterActive() {
+		sb.WriteString("\n" + styles.Description.Render(p.filterStatus()))
+	}
+	return styles.Base.Render(sb.String())
+}
+
+// highlighted returns the description of the dependency under the
+// cursor, cut to the width of the form.
+func (p *depPicker) highlighted() string {
+	if !p.focused || p.cursor >= len(p.rows) || p.rows[p.cursor].isHeader() {
+		return ""
+	}
+	desc := p.rows[p.cursor].option.description
+	// The selector and the border of the field take some columns.
+	if w := p.width - 4; w > 1 && len([]rune(desc)) > w {
+		desc = string([]rune(desc)[:w-1]) + "…"
+	}
+	return desc
+}
+
+// headerView renders the header of the category with the number of
+// its selected dependencies.
+func (p *depPicker) headerView(group string) string {
+	arrow := "▾"
+	if p.collapsed[group] && !p.filterActive() {
+		arrow = "▸"
+	}
+	header := arrow + " " + group
+	if n := p.selectedIn(group); n > 0 {
+		header += fmt.Sprintf(" (%d selected)", n)
+	}
+	return p.styles().Title.Render(header)
+}
+
+func (p *depPicker) Focus() tea.Cmd {
+	p.focused = true
+	return nil
+}
+
+func (p *depPicker) Blur() tea.Cmd {
+	p.focused = false
+	return nil
+}
+
+func (p *depPicker) Error() error {
+	return nil
+}
+
+// Run runs the picker in a form of its own.
+func (p *depPicker) Run() error {
+	if p.accessible {
+		return p.runAccessible()
+	}
+	return huh.NewForm(huh.NewGroup(p)).Run()
+}
+
+// runAccessible lists the dependencies with their ids by category
+// and asks the comma separated ids of the ones to add.
+func (p *depPicker) runAccessible() error {
+	if p.prepare != nil {
+		p.prepare()
+	}
+	var sb strings.Builder
+	sb.WriteString(p.title + "\n")
+	if p.description != "" {
+		sb.WriteString(p.description + "\n")
+	}
+	available := make(map[string]bool)
+	group := ""
+	for _, o := range p.options {
+		available[o.id] = true
+		if o.group != group {
+			group = o.group
+			sb.WriteString("\n" + group + "\n")
+		}
+		line := o.id + ": " + o.label
+		if o.note != "" {
+			line += " " + o.note
+		}
+		sb.WriteString(line + "\n")
+	}
+	out := promptOutput()
+	fmt.Fprintln(out, sb.String())
+
+	if len(*p.value) > 0 {
+		fmt.Fprintf(out, "Selected: %s\n", strings.Join(*p.value, ", "))
+	}
+	fmt.Fprintln(out, "Comma separated ids, empty to keep the selection, none to remove it.")
+	input := accessibility.PromptString("Dependencies: ", func(str string) error {
+		for _, id := range strings.Split(str, ",") {
+			id = strings.TrimSpace(id)
+			if id != "" && id != "none" && !available[id] {
+				return fmt.Errorf("unknown dependency '%s'", id)
+			}
+		}
+		return nil
+	})
+	switch input = strings.TrimSpace(input); input {
+	case "":
+	case "none":
+		*p.value = nil
+	default:
+		*p.value = nil
+		for _, id := range strings.Split(input, ",") {
+			if id = strings.TrimSpace(id); id != "" && !p.isSelected(id) {
+				*p.value = append(*p.value, id)
+			}
+		}
+	}
+	if len(*p.value) == 0 {
+		fmt.Fprint(out, "Selected: none\n\n")
+	} else {
+		fmt.Fprintf(out, "Selected: %s\n\n", strings.Join(*p.value, ", "))
+	}
+	return nil
+}
+
+func (p *depPicker) Skip() bool {
+	return false
+}
+
+func (p *depPicker) Zoom() bool {
+	return false
+}
+
+func (p *depPicker) KeyBinds() []key.Binding {
+	return []key.Binding{p.keymap.Toggle, p.keymap.Up, p.keymap.Down, p.keys.Collapse, p.keys.CollapseAll,
+		p.keymap.Filter, p.keymap.SetFilter, p.keymap.ClearFilter, p.keymap.Prev, p.keymap.Submit, p.keymap.Next}
+}
+
+func (p *depPicker) WithTheme(theme *huh.Theme) huh.Field {
+	p.theme = theme
+	p.filter.Cursor.Style = theme.Focused.TextInput.Cursor
+	p.filter.PromptStyle = theme.Focused.TextInput.Prompt
+	return p
+}
+
+func (p *depPicker) WithAccessible(accessible bool) huh.Field {
+	p.accessible = accessible
+	return p
+}
+
+func (p *depPicker) WithKeyMap(k *huh.KeyMap) huh.Field {
+	p.keymap = k.MultiSelect
+	return p
+}
+
+func (p *depPicker) WithWidth(width int) huh.Field {
+	p.width = width
+	return p
+}
+
+func (p *depPicker) WithHeight(int) huh.Field {
+	return p
+}
+
+func (p *depPicker) WithPosition(pos huh.FieldPosition) huh.Field {
+	if p.filtering {
+		return p
+	}
+	p.keymap.Prev.SetEnabled(!pos.IsFirst())
+	p.keymap.Next.SetEnabled(!pos.IsLast())
+	p.keymap.Submit.SetEnabled(pos.IsLast())
+	return p
+}
+
+func (p *depPicker) GetKey() string {
+	return ""
+}
+
+func (p *depPicker) GetValue() any {
+	return *p.value
+}
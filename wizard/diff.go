@@ -0,0 +1,321 @@
+package wizard
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// diffContext is the number of unchanged lines around a change.
+const diffContext = 3
+
+// runDiff runs the diff command which shows the difference of the
+// build files of two project configurations.
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	from := fs.String("from", "", "config file of the original project")
+	to := fs.String("to", "", "config file of the changed project")
+	var httpOpts httpOptions
+	httpFlags(fs, &httpOpts)
+	fs.Parse(args)
+	if *from == "" || *to == "" {
+		die(fmt.Errorf("both -from and -to are required"))
+	}
+
+	client, err := newClient(httpOpts)
+	if err != nil {
+		die(err)
+	}
+	data, err := getMetaData(client)
+	if err != nil {
+		die(err)
+	}
+
+	a, err := configBuildFile(client, data, *from)
+	if err != nil {
+		die(err)
+	}
+	b, err := configBuildFile(client, data, *to)
+	if err != nil {
+		die(err)
+	}
+
+	if d := unifiedDiff(*from, *to, a, b); d != "" {
+		fmt.Print(d)
+		exit(1)
+	}
+}
+
+// configBuildFile fetches the build file of the project of the
+// config file.
+func configBuildFile(client *initializr.Client, data *metadata, path string) (string, error) {
+	info, err := readConfig(path)
+	if err != nil {
+		return "", err
+	}
+	applyDefaults(info, data)
+	if err := validateCoordinates(info); err != nil {
+		return "", err
+	}
+
+	action, err := data.Action(data.BuildType(info.projectType), "build")
+	if err != nil {
+		return "", err
+	}
+	resp, err := getProjectFile(context.Background(), client, action, info)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to generate build file of %s: %s", path, resp.Status)
+	}
+	b, err := io.ReadAll(resp.Body)
+	return string(b), err
+}
+
+// edit is a line of the edit script of a diff. Its kind is ' ' for
+// an unchanged line, '-' for a removed line and '+' for an added
+// line. aPos and bPos are the number of lines of the old and the
+// new text before the line.
+type edit struct {
+	kind       byte
+	line       string
+	aPos, bPos int
+}
+
+// unifiedDiff returns the unified diff of the old text a and the
+// new text b, or an empty string if they are the same.
+func unifiedDiff(aName, bName, a, b string) string {
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var changes []int
+	for i, e := range edits {
+		if e.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n%s\n",
+		failStyle.Render("--- "+aName), okStyle.Render("+++ "+bName))
+	for i := 0; i < len(changes); {
+		start := changes[i] - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := changes[i] + diffContext + 1
+		// Merge the changes whose context overlaps into one hunk.
+		for i++; i < len(changes) && changes[i]-diffContext <= end; i++ {
+			end = changes[i] + diffContext + 1
+		}
+		if end > len(edits) {
+			end = len(edits)
+		}
+		writeHunk(&sb, edits[start:end])
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, hunk []edit) {
+	aCount, bCount := 0, 0
+	for _, e := range hunk {
+		if e.kind != '+' {
+			aCount++
+		}
+		if e.kind != '-' {
+			bCount++
+		}
+	}
+	aStart, bStart := hunk[0].aPos, hunk[0].bPos
+	if aCount > 0 {
+		aStart++
+	}
+	if bCount > 0 {
+		bStart++
+	}
+
+	sb.WriteString(hintStyle.Render(fmt.Sprintf("@@ -%d,%d +%d,%d @@", aStart, aCount, bStart, bCount)))
+	sb.WriteByte('\n')
+	for _, e := range hunk {
+		line := string(e.kind) + e.line
+		switch e.kind {
+		case '-':
+			line = failStyle.Render(line)
+		case '+':
+			line = okStyle.Render(line)
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+}
+
+// diffLines returns the edit script which turns the lines a into
+// the lines b using their longest common subsequence.
+func diffLines(a, b []string) []edit {
+	// lcs[i][j] is the length of the longest common subsequence
+	// of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var edits []edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i], i, j})
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{'-', a[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j], i, j})
+			j++
+		}
+	}
+	return edits
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// change replaces the lines [start, end) of the base text with
+// the lines.
+type change struct {
+	start, end int
+	lines      []string
+}
+
+// changes returns the changes which turn the lines base into the
+// lines b.
+func changes(base, b []string) []change {
+	var cs []change
+	var cur *change
+	for _, e := range diffLines(base, b) {
+		if e.kind == ' ' {
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			cs = append(cs, change{start: e.aPos, end: e.aPos})
+			cur = &cs[len(cs)-1]
+		}
+		if e.kind == '-' {
+			cur.end++
+		} else {
+			cur.lines = append(cur.lines, e.line)
+		}
+	}
+	return cs
+}
+
+// applyChanges applies the changes to the lines [start, end) of the
+// base text. The changes have to be inside the range.
+func applyChanges(base []string, cs []change, start, end int) []string {
+	var out []string
+	for _, c := range cs {
+		out = append(out, base[start:c.start]...)
+		out = append(out, c.lines...)
+		start = c.end
+	}
+	return append(out, base[start:end]...)
+}
+
+// merge3 merges the changes of ours and theirs to their common base
+// text. The lines changed differently by both are marked as
+// conflicts as git does, and the number of conflicts is returned.
+func merge3(base, ours, theirs string, oursName, theirsName string) (string, int) {
+	baseLines := splitLines(base)
+	oc := changes(baseLines, splitLines(ours))
+	tc := changes(baseLines, splitLines(theirs))
+
+	var out []string
+	conflicts := 0
+	pos, i, j := 0, 0, 0
+	for i < len(oc) || j < len(tc) {
+		// Start a region with the first change of either side and
+		// extend it with the changes which overlap or touch it.
+		var start, end int
+		if j == len(tc) || i < len(oc) && oc[i].start <= tc[j].start {
+			start, end = oc[i].start, oc[i].end
+		} else {
+			start, end = tc[j].start, tc[j].end
+		}
+		oi, tj := i, j
+		for {
+			if i < len(oc) && oc[i].start <= end {
+				if oc[i].end > end {
+					end = oc[i].end
+				}
+				i++
+			} else if j < len(tc) && tc[j].start <= end {
+				if tc[j].end > end {
+					end = tc[j].end
+				}
+				j++
+			} else {
+				break
+			}
+		}
+
+		out = append(out, baseLines[pos:start]...)
+		o := applyChanges(baseLines, oc[oi:i], start, end)
+		t := applyChanges(baseLines, tc[tj:j], start, end)
+		switch {
+		case oi == i:
+			out = append(out, t...)
+		case tj == j || equalLines(o, t):
+			out = append(out, o...)
+		default:
+			conflicts++
+			out = append(out, "<<<<<<< "+oursName)
+			out = append(out, o...)
+			out = append(out, "=======")
+			out = append(out, t...)
+			out = append(out, ">>>>>>> "+theirsName)
+		}
+		pos = end
+	}
+	out = append(out, baseLines[pos:]...)
+	if len(out) == 0 {
+		return "", conflicts
+	}
+	return strings.Join(out, "\n") + "\n", conflicts
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+package wizard
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		wantConflicts      int
+	}{
+		{
+			name:   "no changes",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nc\n",
+			want:   "a\nb\nc\n",
+		},
+		{
+			name:   "only ours",
+			base:   "a\nb\nc\n",
+			ours:   "a\nB\nc\n",
+			theirs: "a\nb\nc\n",
+			want:   "a\nB\nc\n",
+		},
+		{
+			name:   "only theirs",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nc\nd\n",
+			want:   "a\nb\nc\nd\n",
+		},
+		{
+			name:   "separate changes",
+			base:   "a\nb\nc\nd\ne\n",
+			ours:   "A\nb\nc\nd\ne\n",
+			theirs: "a\nb\nc\nd\nE\n",
+			want:   "A\nb\nc\nd\nE\n",
+		},
+		{
+			name:   "same change on both sides",
+			base:   "a\nb\nc\n",
+			ours:   "a\nB\nc\n",
+			theirs: "a\nB\nc\n",
+			want:   "a\nB\nc\n",
+		},
+		{
+			name:   "same removal on both sides",
+			base:   "a\nb\nc\n",
+			ours:   "a\nc\n",
+			theirs: "a\nc\n",
+			want:   "a\nc\n",
+		},
+		{
+			name:          "conflict",
+			base:          "a\nb\nc\n",
+			ours:          "a\nours\nc\n",
+			theirs:        "a\ntheirs\nc\n",
+			want:          "a\n<<<<<<< ours\nours\n=======\ntheirs\n>>>>>>> theirs\nc\n",
+			wantConflicts: 1,
+		},
+		{
+			name:          "removed by ours and changed by theirs",
+			base:          "a\nb\nc\n",
+			ours:          "a\nc\n",
+			theirs:        "a\nB\nc\n",
+			want:          "a\n<<<<<<< ours\n=======\nB\n>>>>>>> theirs\nc\n",
+			wantConflicts: 1,
+		},
+		{
+			name:   "empty base with the same content",
+			base:   "",
+			ours:   "a\n",
+			theirs: "a\n",
+			want:   "a\n",
+		},
+		{
+			name:          "empty base with different content",
+			base:          "",
+			ours:          "a\n",
+			theirs:        "b\n",
+			want:          "<<<<<<< ours\na\n=======\nb\n>>>>>>> theirs\n",
+			wantConflicts: 1,
+		},
+		{
+			name:   "empty ours",
+			base:   "a\nb\n",
+			ours:   "",
+			theirs: "a\nb\n",
+			want:   "",
+		},
+		{
+			name:   "empty theirs",
+			base:   "a\nb\n",
+			ours:   "a\nb\n",
+			theirs: "",
+			want:   "",
+		},
+		{
+			// As in git, changes which touch each other conflict.
+			name:          "empty theirs and added by ours",
+			base:          "a\nb\n",
+			ours:          "a\nb\nc\n",
+			theirs:        "",
+			want:          "<<<<<<< ours\na\nb\nc\n=======\n>>>>>>> theirs\n",
+			wantConflicts: 1,
+		},
+		{
+			name: "all empty",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := merge3(tt.base, tt.ours, tt.theirs, "ours", "theirs")
+			if got != tt.want || conflicts != tt.wantConflicts {
+				t.Errorf("got %d conflicts in\n%s\nwant %d in\n%s", conflicts, got, tt.wantConflicts, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	if got := unifiedDiff("a", "b", "x\ny\n", "x\ny\n"); got != "" {
+		t.Errorf("got a diff of the same texts:\n%s", got)
+	}
+
+	got := unifiedDiff("a/pom.xml", "b/pom.xml", "1\n2\n3\n4\n5\n", "1\n2\nthree\n4\n5\n6\n")
+	want := "--- a/pom.xml\n+++ b/pom.xml\n" +
+		"@@ -1,5 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n+6\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	got = unifiedDiff("a", "b", "", "new\n")
+	want = "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+new\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,197 @@
+package wizard
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+var (
+	okStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#50fa7b"))
+	failStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555"))
+)
+
+// finding is the result of a check of the doctor command.
+type finding struct {
+	ok     bool
+	title  string
+	detail string
+	// fix is the suggested action when the check fails.
+	fix string
+}
+
+// runDoctor runs the doctor command which checks the environment
+// for the common causes of a failed generation.
+func runDoctor(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	var httpOpts httpOptions
+	httpFlags(fs, &httpOpts)
+	fs.Parse(args)
+	// The server itself is checked, not the cached metadata.
+	httpOpts.cacheTTL = -1
+
+	client, err := newClient(httpOpts)
+	if err != nil {
+		die(err)
+	}
+	client.HTTPClient.Timeout = 30 * time.Second
+
+	checks := []func() finding{
+		func() finding { return checkProxy(client.URL) },
+		func() finding { return checkTLS(client.URL) },
+		func() finding { return checkServer(client) },
+		checkWritable,
+		checkJDK,
+		checkGit,
+	}
+
+	failed := false
+	for _, check := range checks {
+		f := check()
+		mark := okStyle.Render("✓")
+		if !f.ok {
+			mark = failStyle.Render("✗")
+			failed = true
+		}
+		fmt.Printf("%s %s\n", mark, f.title)
+		if f.detail != "" {
+			fmt.Printf("  %s\n", f.detail)
+		}
+		if !f.ok && f.fix != "" {
+			fmt.Printf("  %s\n", hintStyle.Render(f.fix))
+		}
+	}
+	if failed {
+		exit(1)
+	}
+}
+
+func checkProxy(server string) finding {
+	req, _ := http.NewRequest(http.MethodGet, server, nil)
+	proxy, err := http.ProxyFromEnvironment(req)
+	if err != nil {
+		return finding{
+			title:  "Proxy configuration",
+			detail: err.Error(),
+			fix:    "Check the HTTPS_PROXY and NO_PROXY environment variables",
+		}
+	}
+	if proxy == nil {
+		return finding{ok: true, title: "Proxy configuration", detail: "no proxy is used"}
+	}
+	return finding{ok: true, title: "Proxy configuration", detail: "using proxy " + redact(proxy)}
+}
+
+// redact hides the password of the proxy url.
+func redact(u *url.URL) string {
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+	return u.String()
+}
+
+func checkTLS(server string) finding {
+	u, err := url.Parse(server)
+	if err != nil || u.Scheme != "https" {
+		return finding{ok: true, title: "TLS", detail: "the server does not use TLS"}
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", host, nil)
+	if err != nil {
+		fix := "Check your network connection"
+		if strings.Contains(err.Error(), "certificate") {
+			fix = "A proxy may intercept TLS, add its CA certificate with the SSL_CERT_FILE environment variable"
+		}
+		return finding{title: "TLS connection to " + u.Host, detail: err.Error(), fix: fix}
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	detail := ""
+	if len(certs) > 0 {
+		detail = "certificate issued by " + certs[0].Issuer.String()
+	}
+	return finding{ok: true, title: "TLS connection to " + u.Host, detail: detail}
+}
+
+func checkServer(client *initializr.Client) finding {
+	start := time.Now()
+	data, err := getMetaData(client)
+	if err != nil {
+		return finding{
+			title:  "Metadata of " + client.URL,
+			detail: err.Error(),
+			fix:    "Check that the server is reachable from this network",
+		}
+	}
+
+	apiVersion := data.APIVersion
+	if apiVersion == "" {
+		apiVersion = "unknown"
+	}
+	return finding{
+		ok:    true,
+		title: "Metadata of " + client.URL,
+		detail: fmt.Sprintf("fetched in %s, format %s, %d boot versions",
+			time.Since(start).Round(time.Millisecond), apiVersion, len(data.BootVersion.Values)),
+	}
+}
+
+func checkWritable() finding {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return finding{title: "Write permission", detail: err.Error()}
+	}
+
+	f, err := os.CreateTemp(cwd, ".startspring-")
+	if err != nil {
+		return finding{
+			title:  "Write permission in " + cwd,
+			detail: err.Error(),
+			fix:    "Run startspring in a directory where you can create files",
+		}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return finding{ok: true, title: "Write permission in " + cwd}
+}
+
+func checkJDK() finding {
+	out, err := javacVersion()
+	if err != nil {
+		return finding{
+			title:  "JDK",
+			detail: err.Error(),
+			fix:    "Install a JDK and set JAVA_HOME to run the generated project",
+		}
+	}
+	return finding{ok: true, title: "JDK", detail: out}
+}
+
+func checkGit() finding {
+	out, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		return finding{
+			title:  "Git",
+			detail: err.Error(),
+			fix:    "Install git to version control the generated project",
+		}
+	}
+	return finding{ok: true, title: "Git", detail: strings.TrimSpace(string(out))}
+}
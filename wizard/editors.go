@@ -0,0 +1,195 @@
+package wizard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// editor is a command which opens the project directory, which is
+// passed as its last argument.
+type editor struct {
+	name    string
+	command []string
+}
+
+// builtinEditors are the editors which are detected without any
+// configuration.
+var builtinEditors = []editor{
+	{name: "code", command: []string{"code"}},
+	{name: "idea", command: []string{"idea"}},
+}
+
+// editorsFile returns the file of the editor commands, e.g.
+// ~/.config/startspring/editors.yaml. A command replaces the one of
+// the built-in editor of the same name or adds a new editor, and is
+// given as a list if its path has spaces:
+//
+//	code: code --new-window
+//	idea: ["/Applications/IntelliJ IDEA.app/Contents/MacOS/idea"]
+//	zed: zed
+func editorsFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "startspring", "editors.yaml"), nil
+}
+
+// editorCommand is the command of a configured editor, either a
+// command line or the list of its arguments.
+type editorCommand []string
+
+func (c *editorCommand) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		*c = strings.Fields(n.Value)
+		return nil
+	}
+	var args []string
+	if err := n.Decode(&args); err != nil {
+		return err
+	}
+	*c = args
+	return nil
+}
+
+// readEditors reads the configured editor commands. There are none if
+// the file does not exist.
+func readEditors() (map[string]editorCommand, error) {
+	path, err := editorsFile()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var editors map[string]editorCommand
+	if err := yaml.NewDecoder(bytes.NewReader(b)).Decode(&editors); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid editors %s: %w", path, err)
+	}
+	for name, command := range editors {
+		if len(command) == 0 {
+			return nil, fmt.Errorf("invalid editors %s: no command for %s", path, name)
+		}
+	}
+	return editors, nil
+}
+
+// allEditors returns the built-in editors, the configured ones and
+// $EDITOR as editor, in the order they are offered.
+func allEditors(configured map[string]editorCommand) []editor {
+	var editors []editor
+	builtin := make(map[string]bool)
+	for _, e := range builtinEditors {
+		builtin[e.name] = true
+		if command, ok := configured[e.name]; ok {
+			e.command = command
+		}
+		editors = append(editors, e)
+	}
+
+	var names []string
+	for name := range configured {
+		if !builtin[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		editors = append(editors, editor{name: name, command: configured[name]})
+	}
+
+	if _, ok := configured["editor"]; !ok {
+		if command := strings.Fields(os.Getenv("EDITOR")); len(command) > 0 {
+			editors = append(editors, editor{name: "editor", command: command})
+		}
+	}
+	return editors
+}
+
+// detectEditors returns the editors whose command is found.
+func detectEditors(configured map[string]editorCommand) []editor {
+	var found []editor
+	for _, e := range allEditors(configured) {
+		if _, err := exec.LookPath(e.command[0]); err == nil {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// findEditor returns the editor of the name, or the first detected
+// one for auto.
+func findEditor(name string, configured map[string]editorCommand) (editor, error) {
+	if name == "auto" {
+		found := detectEditors(configured)
+		if len(found) == 0 {
+			return editor{}, errors.New("no editor found, install code or idea, set $EDITOR or add one to editors.yaml")
+		}
+		return found[0], nil
+	}
+
+	var names []string
+	for _, e := range allEditors(configured) {
+		if e.name != name {
+			names = append(names, e.name)
+			continue
+		}
+		if _, err := exec.LookPath(e.command[0]); err != nil {
+			return editor{}, fmt.Errorf("editor %s not found: %w", name, err)
+		}
+		return e, nil
+	}
+	return editor{}, fmt.Errorf("unknown editor '%s', should be auto or one of %s", name, strings.Join(names, ", "))
+}
+
+// listEditors prints the editors and whether they are found.
+func listEditors(configured map[string]editorCommand) {
+	for _, e := range allEditors(configured) {
+		mark := okStyle.Render("✓")
+		if _, err := exec.LookPath(e.command[0]); err != nil {
+			mark = failStyle.Render("✗")
+		}
+		fmt.Printf("%s %s\n  %s\n", mark, e.name, hintStyle.Render(strings.Join(e.command, " ")))
+	}
+}
+
+func (e editor) cmd(dir string) *exec.Cmd {
+	args := append(append([]string{}, e.command[1:]...), dir)
+	return exec.Command(e.command[0], args...)
+}
+
+// openInEditor opens the directory in the editor and waits for it,
+// as the editors which run in the terminal need it until they quit.
+func openInEditor(e editor, dir string) error {
+	cmd := e.cmd(dir)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to open the project in %s: %w", e.name, err)
+	}
+	return nil
+}
+
+// openEditorCmd opens the directory in the editor, which gets the
+// terminal until it quits.
+func openEditorCmd(e editor, dir string) tea.Cmd {
+	return tea.ExecProcess(e.cmd(dir), func(err error) tea.Msg {
+		if err != nil {
+			return noticeMsg(fmt.Sprintf("Failed to open the project in %s: %v", e.name, err))
+		}
+		return noticeMsg("Opened the project in " + e.name)
+	})
+}
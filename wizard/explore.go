@@ -0,0 +1,161 @@
+package wizard
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	titleStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#bd93f9"))
+	cursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff79c6"))
+)
+
+// explorer lets the user browse the files of the project archive
+// before it is extracted.
+type explorer struct {
+	files    []*zip.File
+	cursor   int
+	offset   int
+	height   int
+	viewing  bool
+	viewport viewport.Model
+}
+
+func newExplorer(a *archive) (explorer, error) {
+	zipReader, err := a.reader()
+	if err != nil {
+		return explorer{}, err
+	}
+
+	var files []*zip.File
+	for _, zf := range zipReader.File {
+		if !zf.FileInfo().IsDir() {
+			files = append(files, zf)
+		}
+	}
+
+	e := explorer{files: files, viewport: viewport.New(80, 20)}
+	e.setSize(80, 24)
+	// Start at the build file of the project as it is the one
+	// which is checked the most.
+	for i, zf := range files {
+		if strings.Count(zf.Name, "/") <= 1 && buildFiles[path.Base(zf.Name)] {
+			e.cursor = i
+			break
+		}
+	}
+	e.scroll()
+	return e, nil
+}
+
+// setSize fits the explorer in the given window size leaving
+// space for the title and the help line.
+func (e *explorer) setSize(width, height int) {
+	e.height = height - 4
+	if e.height < 1 {
+		e.height = 1
+	}
+	e.viewport.Width = width
+	e.viewport.Height = e.height
+}
+
+func (e explorer) Update(msg tea.Msg) (explorer, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		e.setSize(msg.Width, msg.Height)
+
+	case tea.KeyMsg:
+		if e.viewing {
+			switch msg.String() {
+			case "esc", "q", "left", "h":
+				e.viewing = false
+				return e, nil
+			}
+			var cmd tea.Cmd
+			e.viewport, cmd = e.viewport.Update(msg)
+			return e, cmd
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if e.cursor > 0 {
+				e.cursor--
+			}
+		case "down", "j":
+			if e.cursor < len(e.files)-1 {
+				e.cursor++
+			}
+		case "enter", "right", "l":
+			if len(e.files) == 0 {
+				break
+			}
+			content, err := readZipFile(e.files[e.cursor])
+			if err != nil {
+				content = err.Error()
+			}
+			e.viewport.SetContent(content)
+			e.viewport.GotoTop()
+			e.viewing = true
+		}
+
+		e.scroll()
+	}
+	return e, nil
+}
+
+// scroll keeps the cursor in view.
+func (e *explorer) scroll() {
+	if e.cursor < e.offset {
+		e.offset = e.cursor
+	} else if e.cursor >= e.offset+e.height {
+		e.offset = e.cursor - e.height + 1
+	}
+}
+
+func (e explorer) View() string {
+	var sb strings.Builder
+	if e.viewing {
+		sb.WriteString(titleStyle.Render(e.files[e.cursor].Name))
+		sb.WriteString("\n\n")
+		sb.WriteString(e.viewport.View())
+		sb.WriteString("\n")
+		sb.WriteString(hintStyle.Render(fmt.Sprintf(
+			"↑/↓ scroll • esc back • %3.f%%", e.viewport.ScrollPercent()*100)))
+		return sb.String()
+	}
+
+	sb.WriteString(titleStyle.Render("Explore the project"))
+	sb.WriteString("\n\n")
+	for i := e.offset; i < len(e.files) && i < e.offset+e.height; i++ {
+		if i == e.cursor {
+			sb.WriteString(cursorStyle.Render("> " + e.files[i].Name))
+		} else {
+			sb.WriteString("  " + e.files[i].Name)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(hintStyle.Render("↑/↓ move • enter view • x extract • b back to the review • q abort"))
+	return sb.String()
+}
+
+// readZipFile reads the whole content of a file of the archive.
+func readZipFile(zf *zip.File) (string, error) {
+	r, err := zf.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
@@ -0,0 +1,467 @@
+package wizard
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// maxPath is the length of a path which Windows and many tools on it
+// support without long paths enabled.
+const maxPath = 260
+
+// reservedNames are the device names which can not be used as a file
+// name on Windows, even with an extension.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// checkWindowsName checks that every segment of the slash separated
+// path is a valid file name on Windows.
+func checkWindowsName(name string) error {
+	for _, seg := range strings.Split(name, "/") {
+		if seg == "" {
+			continue
+		}
+		base, _, _ := strings.Cut(seg, ".")
+		switch {
+		case reservedNames[strings.ToUpper(base)]:
+			return fmt.Errorf("'%s' is a reserved file name on Windows", seg)
+		case strings.ContainsAny(seg, `<>:"|?*\`):
+			return fmt.Errorf("'%s' contains a character which is not allowed on Windows", seg)
+		case strings.HasSuffix(seg, ".") || strings.HasSuffix(seg, " "):
+			return fmt.Errorf("'%s' ends with a dot or a space which Windows drops", seg)
+		}
+	}
+	return nil
+}
+
+// End of line conversions of the extracted text files.
+const (
+	eolKeep = "keep"
+	eolLF   = "lf"
+	eolCRLF = "crlf"
+)
+
+// lineEnding returns the line ending of the extracted file. The batch
+// wrappers always need CRLF to run with cmd.exe, and the shell
+// wrappers always need LF to run with sh. The other files keep their
+// line endings if eol is empty.
+func lineEnding(name, eol string) string {
+	base := path.Base(name)
+	switch {
+	case strings.HasSuffix(base, ".cmd") || strings.HasSuffix(base, ".bat"):
+		return eolCRLF
+	case base == "mvnw" || base == "gradlew" || strings.HasSuffix(base, ".sh"):
+		return eolLF
+	case eol == "":
+		return eolKeep
+	}
+	return eol
+}
+
+// convertEOL converts the line endings of the text content. Binary
+// content, which has a NUL byte like a jar, is left as it is.
+func convertEOL(content []byte, eol string) []byte {
+	if eol == eolKeep || bytes.IndexByte(content, 0) >= 0 {
+		return content
+	}
+	lf := bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	if eol == eolCRLF {
+		return bytes.ReplaceAll(lf, []byte("\n"), []byte("\r\n"))
+	}
+	return lf
+}
+
+// longPathWarning returns a warning if a path of the directory is too
+// long for the tools on Windows without long paths enabled.
+func longPathWarning(dir string) string {
+	if runtime.GOOS != "windows" {
+		return ""
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	long := false
+	filepath.Walk(abs, func(p string, _ os.FileInfo, err error) error {
+		if err == nil && len(p) >= maxPath {
+			long = true
+			return filepath.SkipDir
+		}
+		return err
+	})
+	if !long {
+		return ""
+	}
+	return fmt.Sprintf("Some paths are longer than %d characters, enable long paths in Windows "+
+		"(LongPathsEnabled) and git (core.longpaths) to build the project", maxPath)
+}
+
+// Policies for a directory of the project which exists and is not
+// empty.
+const (
+	existingFail      = "fail"
+	existingOverwrite = "overwrite"
+	existingMerge     = "merge"
+)
+
+// checkDir checks that the project can be extracted into the
+// directory. A missing or an empty directory is always fine, a
+// directory with files only if the policy allows it.
+func checkDir(dir, policy string) error {
+	fi, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("a file named '%s' already exists", dir)
+	}
+
+	switch policy {
+	case existingOverwrite:
+		return checkOverwrite(dir)
+	case existingMerge:
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("a directory named '%s' already exists and is not empty", dir)
+	}
+	return nil
+}
+
+// checkOverwrite refuses to overwrite the directories which hold
+// more than a project, like the current or the home directory.
+func checkOverwrite(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if filepath.Dir(abs) == abs {
+		return fmt.Errorf("'%s' is the root directory and can not be overwritten", dir)
+	}
+	var protected []string
+	if cwd, err := os.Getwd(); err == nil {
+		protected = append(protected, cwd)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		protected = append(protected, home)
+	}
+	for _, p := range protected {
+		if p == abs || strings.HasPrefix(p, abs+string(filepath.Separator)) {
+			return fmt.Errorf("'%s' holds more than the project and can not be overwritten", dir)
+		}
+	}
+	return nil
+}
+
+// prepareDir creates the directory of the project and returns the
+// directory to extract the project into. The files of a merged
+// directory are replaced by the ones of the project with the same
+// name. An overwritten directory is left as it is until the project
+// is complete: the project is extracted into a temporary directory
+// next to it, which replaceDir puts in its place.
+func prepareDir(dir, policy string) (string, error) {
+	if err := checkDir(dir, policy); err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(dir)
+	if policy != existingOverwrite || os.IsNotExist(err) {
+		return dir, os.MkdirAll(dir, 0777)
+	} else if err != nil {
+		return "", err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+"-*")
+	if err != nil {
+		return "", err
+	}
+	// The temporary directory is only accessible by the user.
+	if err := os.Chmod(tmp, fi.Mode().Perm()); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return tmp, nil
+}
+
+// replaceDir replaces the directory by the temporary one which holds
+// the new project. The old directory is moved aside first and only
+// removed once the new one is in its place, and it is moved back if
+// that fails.
+func replaceDir(tmp, dir string) error {
+	old, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+"-old-*")
+	if err != nil {
+		return err
+	}
+	// The old directory can only be renamed to a path which does not
+	// exist on Windows.
+	if err := os.Remove(old); err != nil {
+		return err
+	}
+	if err := os.Rename(dir, old); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		os.Rename(old, dir)
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+// extraction extracts the entries of an archive into the directory
+// and remembers what it creates, so that it can be removed again if
+// the extraction fails.
+type extraction struct {
+	dir string
+	// root is the directory with its links resolved.
+	root string
+	// created holds the files and the topmost directories which did
+	// not exist before the extraction.
+	created []string
+	// ownDir is set if the directory itself was created for the
+	// extraction.
+	ownDir bool
+	// links are the links which the extraction created.
+	links []string
+}
+
+func newExtraction(dir string, ownDir bool) (*extraction, error) {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &extraction{dir: dir, root: root, ownDir: ownDir}, nil
+}
+
+// extract extracts the entry with the slash separated name. The line
+// endings of a text file are converted to eol.
+func (x *extraction) extract(zf *zip.File, name, eol string) error {
+	if path.IsAbs(name) || filepath.IsAbs(filepath.FromSlash(name)) || filepath.VolumeName(filepath.FromSlash(name)) != "" {
+		return fmt.Errorf("the archive has an entry with an absolute path: %s", name)
+	}
+	p := filepath.Join(x.dir, filepath.FromSlash(name))
+	if !within(x.dir, p) {
+		return fmt.Errorf("the archive has an entry outside of the project: %s", name)
+	}
+	if zf.FileInfo().IsDir() {
+		return x.mkdir(p)
+	}
+	if err := x.mkdir(filepath.Dir(p)); err != nil {
+		return err
+	}
+	if zf.Mode()&os.ModeSymlink != 0 {
+		return x.symlink(zf, p, name)
+	}
+	return x.file(zf, p, fileMode(zf, name), eol)
+}
+
+// mkdir creates the directory of an entry and checks that it is not
+// a link to outside of the project.
+func (x *extraction) mkdir(p string) error {
+	x.track(p)
+	if err := os.MkdirAll(p, 0777); err != nil {
+		return err
+	}
+	real, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return err
+	}
+	if !within(x.root, real) {
+		return fmt.Errorf("'%s' links to outside of the project", p)
+	}
+	return nil
+}
+
+func (x *extraction) file(zf *zip.File, p string, mode os.FileMode, eol string) error {
+	r, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// A link in place of the file would be written through.
+	if fi, err := os.Lstat(p); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+	}
+	x.track(p)
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if eol != eolKeep {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(convertEOL(content, eol)); err != nil {
+			return err
+		}
+	} else if _, err := io.Copy(f, r); err != nil {
+		return err
+	}
+	// The mode is only given to a new file, a merged one keeps its
+	// own.
+	if mode&0111 != 0 {
+		if err := f.Chmod(mode); err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// symlink creates the link of the entry, whose content is the target
+// of the link. Links to outside of the project are refused.
+func (x *extraction) symlink(zf *zip.File, p, name string) error {
+	r, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	target := filepath.FromSlash(string(b))
+	if filepath.IsAbs(target) || !within(x.dir, filepath.Join(filepath.Dir(p), target)) {
+		return fmt.Errorf("the archive has a link to outside of the project: %s -> %s", name, b)
+	}
+
+	x.track(p)
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Symlink(target, p); err != nil {
+		return err
+	}
+	x.links = append(x.links, p)
+	// A link can lead outside through the links which are created
+	// after it, e.g. a -> b/../c with b -> . later, so all of them
+	// are checked again.
+	for _, l := range x.links {
+		if !within(x.root, x.resolveLink(l)) {
+			return fmt.Errorf("the archive has a link to outside of the project: %s", name)
+		}
+	}
+	return nil
+}
+
+// resolveLink returns the path which the link points to with the
+// links on the way resolved, as far as they exist.
+func (x *extraction) resolveLink(l string) string {
+	target, err := os.Readlink(l)
+	if err != nil {
+		return l
+	}
+	p, err := filepath.EvalSymlinks(filepath.Dir(l))
+	if err != nil {
+		return l
+	}
+	for _, seg := range strings.Split(filepath.ToSlash(target), "/") {
+		switch seg {
+		case "", ".":
+		case "..":
+			p = filepath.Dir(p)
+		default:
+			p = filepath.Join(p, seg)
+			if real, err := filepath.EvalSymlinks(p); err == nil {
+				p = real
+			}
+		}
+	}
+	return p
+}
+
+// track remembers the topmost path up to p which does not exist yet.
+func (x *extraction) track(p string) {
+	missing := ""
+	for d := p; d != x.dir && within(x.dir, d); d = filepath.Dir(d) {
+		if _, err := os.Lstat(d); !os.IsNotExist(err) {
+			break
+		}
+		missing = d
+	}
+	if missing != "" {
+		x.created = append(x.created, missing)
+	}
+}
+
+// cleanUp removes what the extraction created. A merged directory
+// keeps the files it had before.
+func (x *extraction) cleanUp() {
+	if x.ownDir {
+		os.RemoveAll(x.dir)
+		return
+	}
+	for i := len(x.created) - 1; i >= 0; i-- {
+		os.RemoveAll(x.created[i])
+	}
+}
+
+// listPaths returns the paths of the files and the directories in the
+// directory. Links are not followed.
+func listPaths(dir string) (map[string]bool, error) {
+	paths := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		paths[p] = true
+		return nil
+	})
+	return paths, err
+}
+
+// removeAdded removes the files and the directories of the directory
+// which are not in the paths listed before.
+func removeAdded(dir string, before map[string]bool) {
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || before[p] {
+			return nil
+		}
+		os.RemoveAll(p)
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// within reports whether the path is in the directory.
+func within(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// fileMode returns the mode of the extracted file. The wrappers are
+// always executable, even if the archive was made without the modes
+// of unix.
+func fileMode(zf *zip.File, name string) os.FileMode {
+	mode := zf.Mode().Perm()
+	if mode == 0 {
+		mode = 0644
+	}
+	if base := path.Base(name); (base == "mvnw" || base == "gradlew") && mode&0111 == 0 {
+		mode = 0755
+	}
+	return mode
+}
@@ -0,0 +1,365 @@
+package wizard
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// zipEntry is an entry of an archive built by the tests.
+type zipEntry struct {
+	name    string
+	content string
+	mode    os.FileMode
+}
+
+// testArchive builds an archive with the entries in memory.
+func testArchive(t *testing.T, entries ...zipEntry) *archive {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		fh := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		mode := e.mode
+		if mode == 0 {
+			mode = 0644
+		}
+		fh.SetMode(mode)
+		w, err := zw.CreateHeader(fh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	a, err := newArchive(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(a.remove)
+	return a
+}
+
+// testUnzip extracts the archive into dir/demo with the policy.
+func testUnzip(t *testing.T, a *archive, dir, policy string) error {
+	t.Helper()
+	return testUnzipFinish(t, a, dir, policy, nil)
+}
+
+// testUnzipFinish extracts the archive into dir/demo with the policy
+// and runs finish on the extracted project.
+func testUnzipFinish(t *testing.T, a *archive, dir, policy string, finish func(string) error) error {
+	t.Helper()
+	info := &projectInfo{name: "demo", baseDir: "demo", output: filepath.Join(dir, "demo")}
+	_, err := unzip(context.Background(), a, info, options{existing: policy}, finish)
+	return err
+}
+
+// failingFinish writes a file into the project and fails like an
+// add-on which fails halfway.
+func failingFinish(dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, ".devcontainer"), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".devcontainer", "devcontainer.json"), []byte("{}"), 0644); err != nil {
+		return err
+	}
+	return errors.New("add-on failed")
+}
+
+func TestUnzipOverwriteKeepsDirOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "demo")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	old := filepath.Join(project, "old.txt")
+	if err := os.WriteFile(old, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := testArchive(t,
+		zipEntry{name: "demo/pom.xml", content: "<project/>"},
+		zipEntry{name: "demo/../../evil.txt", content: "evil"},
+	)
+	if err := testUnzip(t, a, dir, existingOverwrite); err == nil {
+		t.Fatal("extracted an archive with an entry outside of the project")
+	}
+	if b, err := os.ReadFile(old); err != nil || string(b) != "old" {
+		t.Fatalf("the overwritten directory was changed by a failed extraction: %q, %v", b, err)
+	}
+	if _, err := os.Stat(filepath.Join(project, "pom.xml")); !os.IsNotExist(err) {
+		t.Fatalf("the failed extraction left pom.xml: %v", err)
+	}
+	assertEntries(t, dir, "demo")
+}
+
+func TestUnzipOverwriteReplacesDir(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "demo")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, "old.txt"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := testArchive(t, zipEntry{name: "demo/pom.xml", content: "<project/>"})
+	if err := testUnzip(t, a, dir, existingOverwrite); err != nil {
+		t.Fatal(err)
+	}
+	assertEntries(t, project, "pom.xml")
+	assertEntries(t, dir, "demo")
+}
+
+func TestUnzipRemovesProjectWhenFinishFails(t *testing.T) {
+	dir := t.TempDir()
+	a := testArchive(t, zipEntry{name: "demo/pom.xml", content: "<project/>"})
+	if err := testUnzipFinish(t, a, dir, existingFail, failingFinish); err == nil {
+		t.Fatal("the failure of finish was not returned")
+	}
+	assertEntries(t, dir)
+}
+
+func TestUnzipOverwriteKeepsDirWhenFinishFails(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "demo")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, "old.txt"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := testArchive(t, zipEntry{name: "demo/pom.xml", content: "<project/>"})
+	if err := testUnzipFinish(t, a, dir, existingOverwrite, failingFinish); err == nil {
+		t.Fatal("the failure of finish was not returned")
+	}
+	assertEntries(t, project, "old.txt")
+	assertEntries(t, dir, "demo")
+}
+
+func TestUnzipMergeRemovesAddedFilesWhenFinishFails(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "demo")
+	if err := os.MkdirAll(filepath.Join(project, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, "old.txt"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := testArchive(t,
+		zipEntry{name: "demo/pom.xml", content: "<project/>"},
+		zipEntry{name: "demo/src/App.java", content: "class App {}"},
+	)
+	if err := testUnzipFinish(t, a, dir, existingMerge, failingFinish); err == nil {
+		t.Fatal("the failure of finish was not returned")
+	}
+	assertEntries(t, project, "old.txt", "src")
+	assertEntries(t, filepath.Join(project, "src"))
+}
+
+func TestExtractProjectRemovesProjectOfFailedAddOn(t *testing.T) {
+	dir := t.TempDir()
+	m := model{
+		ctx:  context.Background(),
+		info: &projectInfo{name: "demo", baseDir: "demo", output: filepath.Join(dir, "demo"), addOns: []string{"unknown"}},
+		opts: options{existing: existingFail},
+	}
+	a := testArchive(t, zipEntry{name: "demo/pom.xml", content: "<project/>"})
+	msg, ok := m.extractProject(a)().(errMsg)
+	if !ok {
+		t.Fatalf("got %T, want an errMsg", msg)
+	}
+	assertEntries(t, dir)
+}
+
+func TestExtractProjectCanceledAfterExtraction(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	m := model{
+		ctx:  ctx,
+		info: &projectInfo{name: "demo", baseDir: "demo", output: filepath.Join(dir, "demo"), addOns: []string{"dotenv"}},
+		opts: options{existing: existingFail},
+	}
+	a := testArchive(t, zipEntry{name: "demo/pom.xml", content: "<project/>"})
+	finish := func(dir string) error {
+		cancel()
+		_, _, err := m.finishProject(dir)
+		return err
+	}
+	_, err := unzip(ctx, a, m.info, m.opts, finish)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want the cancellation", err)
+	}
+	if got := m.failed(err).err; got != errCanceled {
+		t.Fatalf("got %v, want %v", got, errCanceled)
+	}
+	assertEntries(t, dir)
+}
+
+func TestUnzipRejectsEscapes(t *testing.T) {
+	link := os.ModeSymlink | 0777
+	tests := []struct {
+		name    string
+		entries []zipEntry
+		// symlinks needs links, which Windows only allows to
+		// administrators.
+		symlinks bool
+	}{
+		{name: "parent in the base directory", entries: []zipEntry{
+			{name: "demo/pom.xml", content: "<project/>"},
+			{name: "demo/../../outside/evil.txt", content: "evil"},
+		}},
+		{name: "parent without the base directory", entries: []zipEntry{
+			{name: "../outside/evil.txt", content: "evil"},
+		}},
+		{name: "absolute path", entries: []zipEntry{
+			{name: "/outside/evil.txt", content: "evil"},
+		}},
+		{name: "absolute path in the base directory", entries: []zipEntry{
+			{name: "demo//outside/evil.txt", content: "evil"},
+		}},
+		{name: "link to a parent", symlinks: true, entries: []zipEntry{
+			{name: "demo/out", content: "../../outside", mode: link},
+			{name: "demo/out/evil.txt", content: "evil"},
+		}},
+		{name: "link to an absolute path", symlinks: true, entries: []zipEntry{
+			{name: "demo/out", content: "/tmp", mode: link},
+		}},
+		{name: "link through a link", symlinks: true, entries: []zipEntry{
+			{name: "demo/src/up", content: "..", mode: link},
+			{name: "demo/src/out", content: "up/../../outside", mode: link},
+		}},
+		{name: "link through a later link", symlinks: true, entries: []zipEntry{
+			{name: "demo/a", content: "b/../outside", mode: link},
+			{name: "demo/b", content: ".", mode: link},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.symlinks && runtime.GOOS == "windows" {
+				t.Skip("links need privileges on Windows")
+			}
+			root := t.TempDir()
+			dir := filepath.Join(root, "work")
+			outside := filepath.Join(root, "outside")
+			for _, d := range []string{dir, outside} {
+				if err := os.MkdirAll(d, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := testUnzip(t, testArchive(t, tt.entries...), dir, existingFail); err == nil {
+				t.Fatal("extracted an archive which escapes the project")
+			}
+			assertEntries(t, outside)
+			assertEntries(t, dir)
+		})
+	}
+}
+
+func TestUnzipRejectsLinkOfMergedDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("links need privileges on Windows")
+	}
+	root := t.TempDir()
+	project := filepath.Join(root, "work", "demo")
+	outside := filepath.Join(root, "outside")
+	for _, d := range []string{project, outside} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A link which the user made in the merged directory is not
+	// written through.
+	if err := os.Symlink(outside, filepath.Join(project, "out")); err != nil {
+		t.Fatal(err)
+	}
+
+	a := testArchive(t, zipEntry{name: "demo/out/evil.txt", content: "evil"})
+	if err := testUnzip(t, a, filepath.Join(root, "work"), existingMerge); err == nil {
+		t.Fatal("extracted through a link to outside of the project")
+	}
+	assertEntries(t, outside)
+	assertEntries(t, project, "out")
+}
+
+func TestUnzipKeepsLinkWithinProject(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("links need privileges on Windows")
+	}
+	dir := t.TempDir()
+	a := testArchive(t,
+		zipEntry{name: "demo/src/main.txt", content: "main"},
+		zipEntry{name: "demo/current", content: "src", mode: os.ModeSymlink | 0777},
+	)
+	if err := testUnzip(t, a, dir, existingFail); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "demo", "current", "main.txt"))
+	if err != nil || string(b) != "main" {
+		t.Fatalf("got %q, %v through the link", b, err)
+	}
+}
+
+func TestCheckWindowsName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"src/main/java/App.java", true},
+		{"console/Console.java", true},
+		{"CON", false},
+		{"con", false},
+		{"aux.txt", false},
+		{"src/AUX.tar.gz", false},
+		{"docs/nul/readme.md", false},
+		{"COM1", false},
+		{"lpt9.log", false},
+		{"a:b.txt", false},
+		{"what?.txt", false},
+		{"dir\\file", false},
+		{"trailing.", false},
+		{"trailing ", false},
+	}
+	for _, tt := range tests {
+		err := checkWindowsName(tt.name)
+		if tt.valid && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		} else if !tt.valid && err == nil {
+			t.Errorf("%s: accepted a name which is invalid on Windows", tt.name)
+		}
+	}
+}
+
+// assertEntries checks that the directory holds exactly the names.
+func assertEntries(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	if len(got) != len(names) {
+		t.Fatalf("%s holds %v, want %v", dir, got, names)
+	}
+	for i := range got {
+		if got[i] != names[i] {
+			t.Fatalf("%s holds %v, want %v", dir, got, names)
+		}
+	}
+}
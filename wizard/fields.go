@@ -0,0 +1,226 @@
+package wizard
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+// descriptionLimit is the maximum number of characters of the
+// description of the project.
+const descriptionLimit = 500
+
+// countedText is a text area which shows the number of characters
+// typed so far below it.
+type countedText struct {
+	*huh.Text
+	value *string
+	limit int
+}
+
+func newCountedText(value *string, limit int) *countedText {
+	return &countedText{
+		Text:  huh.NewText().Value(value).CharLimit(limit),
+		value: value,
+		limit: limit,
+	}
+}
+
+// Update updates the text area and keeps the wrapper as the field
+// of the group.
+func (t *countedText) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	_, cmd := t.Text.Update(msg)
+	return t, cmd
+}
+
+func (t *countedText) View() string {
+	count := fmt.Sprintf("%d/%d", utf8.RuneCountInString(*t.value), t.limit)
+	return t.Text.View() + "\n  " + hintStyle.Render(count)
+}
+
+// jumpSelect is a select which jumps to the next option starting
+// with a typed character. huh.Select does not expose its cursor, but
+// it sets the value to the option under the cursor on every move, so
+// the cursor is read back from the value and a jump moves it with the
+// down key. Filtering is disabled as the typed characters jump
+// instead.
+type jumpSelect struct {
+	*huh.Select[string]
+	value   *string
+	options []huh.Option[string]
+	keymap  huh.SelectKeyMap
+}
+
+// newJumpSelect wraps the select of the options. The cursor starts
+// at the current value, otherwise at the default value as in
+// huh.Select.
+func newJumpSelect(sel *huh.Select[string], value *string, options []huh.Option[string], def string) *jumpSelect {
+	s := &jumpSelect{
+		value:   value,
+		options: options,
+		keymap:  huh.NewDefaultKeyMap().Select,
+	}
+	if s.index(*value) < 0 && s.index(def) >= 0 {
+		*value = def
+	}
+	s.Select = sel.Options(options...).Value(value)
+	return s
+}
+
+func (s *jumpSelect) index(value string) int {
+	for i, o := range s.options {
+		if o.Value == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// cursor returns the index of the option under the cursor.
+func (s *jumpSelect) cursor() int {
+	if i := s.index(*s.value); i >= 0 {
+		return i
+	}
+	return 0
+}
+
+func (s *jumpSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, s.keymap.Filter):
+			return s, nil
+		case key.Matches(msg, s.keymap.Up, s.keymap.Down):
+			// j and k move the cursor rather than jump.
+		case msg.Type == tea.KeyRunes && len(msg.Runes) == 1:
+			labels := make([]string, len(s.options))
+			for i, o := range s.options {
+				labels[i] = o.Key
+			}
+			cursor := s.cursor()
+			i := jumpIndex(labels, cursor, msg.Runes[0])
+			if i < 0 {
+				return s, nil
+			}
+			// The down key wraps around at the end like the jump,
+			// and scrolls the options as a move by hand does.
+			var cmds []tea.Cmd
+			for n := (i - cursor + len(s.options)) % len(s.options); n > 0; n-- {
+				_, cmd := s.Select.Update(tea.KeyMsg{Type: tea.KeyDown})
+				cmds = append(cmds, cmd)
+			}
+			return s, tea.Batch(cmds...)
+		case key.Matches(msg, s.keymap.GotoBottom):
+			// huh.Select does not set the value when it goes to the
+			// end, so it is set here to keep the cursor readable.
+			_, cmd := s.Select.Update(msg)
+			if len(s.options) > 0 {
+				*s.value = s.options[len(s.options)-1].Value
+			}
+			return s, cmd
+		}
+	}
+	_, cmd := s.Select.Update(msg)
+	return s, cmd
+}
+
+func (s *jumpSelect) WithKeyMap(k *huh.KeyMap) huh.Field {
+	s.keymap = k.Select
+	s.Select.WithKeyMap(k)
+	return s
+}
+
+// setOptions replaces the options. The cursor stays on the same
+// option if it is still there, otherwise it moves to the first one.
+func (s *jumpSelect) setOptions(options []huh.Option[string]) {
+	s.options = options
+	if s.index(*s.value) < 0 {
+		*s.value = options[0].Value
+	}
+	s.Select.Options(options...)
+}
+
+// versionSelect is the select of the boot versions which hides the
+// pre-releases unless they are toggled on.
+type versionSelect struct {
+	*jumpSelect
+	all     []huh.Option[string]
+	showPre bool
+	toggle  key.Binding
+}
+
+func newVersionSelect(sel *huh.Select[string], value *string, options []huh.Option[string], def string) *versionSelect {
+	v := &versionSelect{
+		all:    options,
+		toggle: key.NewBinding(key.WithKeys("ctrl+t")),
+	}
+	// A pre-release which is already picked, or is the default of
+	// the server, stays visible.
+	v.showPre = isPreRelease(*value) || *value == "" && isPreRelease(def)
+	v.jumpSelect = newJumpSelect(sel, value, v.visible(), def)
+	v.describe()
+	return v
+}
+
+func (v *versionSelect) describe() {
+	if v.showPre {
+		v.Select.Description("Press ctrl+t to hide the snapshots and the milestones")
+	} else {
+		v.Select.Description("Snapshots and milestones are hidden, press ctrl+t to show them")
+	}
+}
+
+// visible returns the options which are shown, all of them if they
+// are all pre-releases.
+func (v *versionSelect) visible() []huh.Option[string] {
+	if v.showPre {
+		return v.all
+	}
+	var stable []huh.Option[string]
+	for _, o := range v.all {
+		if !isPreRelease(o.Value) {
+			stable = append(stable, o)
+		}
+	}
+	if len(stable) == 0 {
+		return v.all
+	}
+	return stable
+}
+
+func (v *versionSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, v.toggle) {
+		v.showPre = !v.showPre
+		v.setOptions(v.visible())
+		v.describe()
+		return v, nil
+	}
+	_, cmd := v.jumpSelect.Update(msg)
+	return v, cmd
+}
+
+func (v *versionSelect) KeyBinds() []key.Binding {
+	help := "show pre-releases"
+	if v.showPre {
+		help = "hide pre-releases"
+	}
+	v.toggle.SetHelp("ctrl+t", help)
+	return append(v.jumpSelect.KeyBinds(), v.toggle)
+}
+
+// jumpIndex returns the index of the next label after the cursor
+// which starts with the character, wrapping around at the end. It
+// returns -1 if there is no such label.
+func jumpIndex(labels []string, cursor int, r rune) int {
+	prefix := strings.ToLower(string(r))
+	for n := 1; n <= len(labels); n++ {
+		i := (cursor + n) % len(labels)
+		if strings.HasPrefix(strings.ToLower(labels[i]), prefix) {
+			return i
+		}
+	}
+	return -1
+}
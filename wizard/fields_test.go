@@ -0,0 +1,42 @@
+package wizard
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+func TestJumpSelectFollowsTheCursorOfHuh(t *testing.T) {
+	value := ""
+	options := huh.NewOptions("Apple", "Banana", "Blueberry", "Cherry", "Brazil nut")
+	s := newJumpSelect(huh.NewSelect[string](), &value, options, "Banana")
+	// The form gives its fields the key map.
+	s.WithKeyMap(huh.NewDefaultKeyMap())
+	if value != "Banana" {
+		t.Fatalf("got %q, want the default Banana", value)
+	}
+
+	keys := []struct {
+		msg  tea.KeyMsg
+		want string
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")}, "Blueberry"},
+		{tea.KeyMsg{Type: tea.KeyEnd}, "Brazil nut"},
+		// The jump starts from the end rather than from Blueberry.
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")}, "Banana"},
+		{tea.KeyMsg{Type: tea.KeyHome}, "Apple"},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")}, "Cherry"},
+		{tea.KeyMsg{Type: tea.KeyUp}, "Blueberry"},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")}, "Brazil nut"},
+		// The jump wraps around at the end.
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}, "Apple"},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}, "Apple"},
+	}
+	for _, k := range keys {
+		s.Update(k.msg)
+		if value != k.want {
+			t.Fatalf("after %s: got %q, want %q", k.msg, value, k.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package wizard
+
+import (
+	"errors"
+	"flag"
+	"strings"
+)
+
+// listFlag is a comma separated list of values.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(s string) error {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+// paramFlag is a repeatable key=value flag which sets the value of an
+// additional field declared by the server.
+type paramFlag struct{ extra *map[string]*string }
+
+func (p paramFlag) String() string {
+	if p.extra == nil {
+		return ""
+	}
+	var params []string
+	for k, v := range *p.extra {
+		params = append(params, k+"="+*v)
+	}
+	return strings.Join(params, ",")
+}
+
+func (p paramFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok {
+		return errors.New("should be in key=value format")
+	}
+	if *p.extra == nil {
+		*p.extra = make(map[string]*string)
+	}
+	(*p.extra)[k] = &v
+	return nil
+}
+
+// projectFlags defines the flags to describe the project in the
+// flag set. The current values of info are used as defaults.
+func projectFlags(fs *flag.FlagSet, info *projectInfo) {
+	fs.StringVar(&info.name, "name", info.name, "name of the project")
+	fs.StringVar(&info.group, "group", info.group, "group id of the project")
+	fs.StringVar(&info.artifact, "artifact", info.artifact, "artifact id of the project")
+	fs.StringVar(&info.version, "project-version", info.version, "version of the project, e.g. 0.0.1-SNAPSHOT")
+	fs.StringVar(&info.description, "description", info.description, "description of the project")
+	fs.StringVar(&info.packageName, "package-name", info.packageName, "package name, derived from group and artifact id if empty")
+	fs.StringVar(&info.baseDir, "base-dir", info.baseDir, "directory of the project, the name of the project if empty")
+	fs.StringVar(&info.language, "language", info.language, "language of the project, e.g. java")
+	fs.StringVar(&info.javaVersion, "java-version", info.javaVersion, "java version, e.g. 17")
+	fs.StringVar(&info.bootVersion, "boot-version", info.bootVersion, "spring boot version")
+	fs.StringVar(&info.projectType, "type", info.projectType, "type of the project, e.g. maven-project")
+	fs.StringVar(&info.packaging, "packaging", info.packaging, "packaging type, e.g. jar")
+	fs.Var((*listFlag)(&info.dependencies), "deps", "comma separated dependency ids, e.g. web,actuator")
+	fs.Var(paramFlag{&info.extra}, "param", "key=value of an additional field of the server, can be repeated")
+}
+
+// projectFlagNames returns the names of the flags defined by
+// projectFlags.
+func projectFlagNames() map[string]bool {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	projectFlags(fs, &projectInfo{})
+	names := make(map[string]bool)
+	fs.VisitAll(func(f *flag.Flag) {
+		names[f.Name] = true
+	})
+	return names
+}
+
+// moduleFlag is a repeatable name=deps flag which declares a module of
+// a workspace with its comma separated dependency ids, e.g. api=web.
+type moduleFlag []workspaceModule
+
+func (m *moduleFlag) String() string {
+	var modules []string
+	for _, mod := range *m {
+		modules = append(modules, mod.name+"="+strings.Join(mod.deps, ","))
+	}
+	return strings.Join(modules, " ")
+}
+
+func (m *moduleFlag) Set(s string) error {
+	name, deps, _ := strings.Cut(s, "=")
+	mod := workspaceModule{name: strings.TrimSpace(name)}
+	if err := validateModuleName(mod.name); err != nil {
+		return err
+	}
+	if err := (*listFlag)(&mod.deps).Set(deps); err != nil {
+		return err
+	}
+	*m = append(*m, mod)
+	return nil
+}
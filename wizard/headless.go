@@ -0,0 +1,139 @@
+package wizard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// runHeadless generates the project described by the flags without
+// the form, for scripts and CI. The missing values are filled with
+// the defaults of the server.
+func runHeadless(data *metadata, client *initializr.Client, info *projectInfo, opts options) {
+	if opts.web || opts.explore {
+		die(errors.New("-web and -explore need the form and can not be used with -no-input"))
+	}
+	info.dependencies, opts.catalog = splitCatalog(info.dependencies, opts.catalog)
+	applyDefaults(info, data)
+	info.bootVersion = resolveBootVersion(data, info.bootVersion)
+	if opts.share {
+		text, err := shareText(client.URL, info, data)
+		if err != nil {
+			die(err)
+		}
+		fmt.Println(text)
+		return
+	}
+	if err := checkHeadless(data, info, opts.existing); err != nil {
+		die(err)
+	}
+
+	// ctrl+c cancels the generation, which removes what it
+	// extracted.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	m := newModel(data, client, info, opts)
+	m.ctx = ctx
+	m.catalogProjects = opts.catalog
+	switch msg := m.generateProject()().(type) {
+	case errMsg:
+		die(msg.err)
+	case generatedMsg:
+		// The json summary on stdout is not mixed with the messages.
+		var out io.Writer = os.Stdout
+		if opts.jsonOut == "-" {
+			out = os.Stderr
+		}
+		if msg.buildFile != "" {
+			fmt.Fprintf(out, "Wrote the build file of %s to %s\n", info.name, msg.buildFile)
+			if opts.jsonOut != "" {
+				files := []string{filepath.Base(msg.buildFile)}
+				if err := writeResult(opts.jsonOut, info, filepath.Dir(msg.buildFile), files); err != nil {
+					die(err)
+				}
+			}
+			openHeadless(opts, filepath.Dir(msg.buildFile))
+			return
+		}
+		for _, w := range msg.warnings {
+			fmt.Fprintln(os.Stderr, warnStyle.Render(w))
+		}
+		if opts.offline {
+			fmt.Fprintln(os.Stderr, warnStyle.Render(
+				"Generated offline from the bundled templates, review the build file before use"))
+		}
+		for _, note := range msg.notes {
+			fmt.Fprintln(out, note)
+		}
+		dir, _ := filepath.Abs(info.dir())
+		fmt.Fprintf(out, "Generated %s in %s\n", info.name, dir)
+		if opts.jsonOut != "" {
+			if err := writeResult(opts.jsonOut, info, dir, msg.files); err != nil {
+				die(err)
+			}
+		}
+		openHeadless(opts, dir)
+	}
+}
+
+// openHeadless opens the generated project in the editor of -open. The
+// project is kept if the editor fails.
+func openHeadless(opts options, dir string) {
+	if opts.open == "" {
+		return
+	}
+	e, err := findEditor(opts.open, opts.editors)
+	if err == nil {
+		err = openInEditor(e, dir)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, warnStyle.Render(err.Error()))
+	}
+}
+
+// checkHeadless checks the values which the form would have checked
+// before they are sent to the server.
+func checkHeadless(data *metadata, info *projectInfo, existing string) error {
+	if err := validateCoordinates(info); err != nil {
+		return err
+	}
+	if data.isBuildType(info.projectType) {
+		action, err := data.Action(info.projectType, "build")
+		if err != nil {
+			return err
+		}
+		if err := checkBuildFile(buildFileTarget(action, info), existing); err != nil {
+			return err
+		}
+	} else if err := checkDir(info.dir(), existing); err != nil {
+		return err
+	}
+
+	known := make(map[string]bool)
+	for _, values := range data.Dependencies.Values {
+		for _, dep := range values.Values {
+			known[dep.Id] = true
+		}
+	}
+	for _, id := range info.dependencies {
+		if !known[id] {
+			return fmt.Errorf("unknown dependency '%s'", id)
+		}
+	}
+	for _, id := range info.addOns {
+		found := false
+		for _, a := range addOns {
+			found = found || a.id == id
+		}
+		if !found {
+			return fmt.Errorf("unknown add-on '%s'", id)
+		}
+	}
+	return nil
+}
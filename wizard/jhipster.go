@@ -0,0 +1,227 @@
+package wizard
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/huh"
+)
+
+// jhipsterImage is the container image which runs JHipster when it
+// is not installed.
+const jhipsterImage = "jhipster/jhipster"
+
+// jhipsterApp is the configuration of a JHipster application in the
+// application block of its JDL.
+type jhipsterApp struct {
+	baseName           string
+	packageName        string
+	applicationType    string
+	authenticationType string
+	buildTool          string
+	clientFramework    string
+	prodDatabaseType   string
+}
+
+// jdl returns the JDL which describes the application. Microservices
+// have no client, so its question is not asked for them.
+func (a jhipsterApp) jdl() string {
+	if a.applicationType == "microservice" {
+		a.clientFramework = "no"
+	}
+	config := []struct{ key, val string }{
+		{"baseName", a.baseName},
+		{"packageName", a.packageName},
+		{"applicationType", a.applicationType},
+		{"authenticationType", a.authenticationType},
+		{"buildTool", a.buildTool},
+		{"clientFramework", a.clientFramework},
+		{"prodDatabaseType", a.prodDatabaseType},
+	}
+
+	var sb strings.Builder
+	sb.WriteString("application {\n  config {\n")
+	for _, c := range config {
+		if c.val != "" {
+			fmt.Fprintf(&sb, "    %s %s\n", c.key, c.val)
+		}
+	}
+	sb.WriteString("  }\n}\n")
+	return sb.String()
+}
+
+// runJHipster runs the jhipster command which asks the questions of
+// a full-stack JHipster application and generates it from a JDL with
+// the jhipster command, or its container image if it is missing.
+func runJHipster(args []string) {
+	fs := flag.NewFlagSet("jhipster", flag.ExitOnError)
+	useDocker := fs.Bool("docker", false, "run JHipster with docker even if it is installed")
+	jdlOnly := fs.Bool("jdl-only", false, "only write the JDL of the application")
+	plain := fs.Bool("plain", false, "use a compact and colorless UI for limited terminals")
+	fs.Parse(args)
+
+	app := jhipsterApp{
+		baseName:           "jhipster",
+		applicationType:    "monolith",
+		authenticationType: "jwt",
+		buildTool:          "maven",
+		clientFramework:    "angular",
+		prodDatabaseType:   "postgresql",
+	}
+	form := newJHipsterForm(&app)
+	if *plain {
+		form = form.WithTheme(plainTheme()).WithShowHelp(false)
+	}
+	if err := form.Run(); err != nil {
+		die(err)
+	}
+	if app.packageName == "" {
+		app.packageName = "com.mycompany." + strings.ToLower(app.baseName)
+	}
+
+	if err := os.Mkdir(app.baseName, 0777); err != nil {
+		die(err)
+	}
+	jdlFile := app.baseName + ".jdl"
+	if err := os.WriteFile(filepath.Join(app.baseName, jdlFile), []byte(app.jdl()), 0666); err != nil {
+		die(err)
+	}
+	if *jdlOnly {
+		fmt.Printf("Wrote %s\n", filepath.Join(app.baseName, jdlFile))
+		return
+	}
+
+	cmd, err := jhipsterCommand(app.baseName, jdlFile, *useDocker)
+	if err != nil {
+		die(err)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		die(fmt.Errorf("jhipster failed: %w", err))
+	}
+}
+
+// jhipsterCommand returns the command which generates the application
+// of the JDL file in the directory.
+func jhipsterCommand(dir, jdlFile string, useDocker bool) (*exec.Cmd, error) {
+	jdlArgs := []string{"jdl", jdlFile, "--skip-install"}
+	if !useDocker {
+		if _, err := exec.LookPath("jhipster"); err == nil {
+			cmd := exec.Command("jhipster", jdlArgs...)
+			cmd.Dir = dir
+			return cmd, nil
+		}
+	}
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, errors.New("neither jhipster nor docker is found, " +
+			"install JHipster with npm install -g generator-jhipster")
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	const workDir = "/home/jhipster/app"
+	dockerArgs := []string{"run", "--rm", "-it", "-v", abs + ":" + workDir, "-w", workDir,
+		jhipsterImage, "jhipster"}
+	return exec.Command("docker", append(dockerArgs, jdlArgs...)...), nil
+}
+
+func newJHipsterForm(app *jhipsterApp) *huh.Form {
+	options := func(values ...string) []huh.Option[string] {
+		var opts []huh.Option[string]
+		for i := 0; i < len(values); i += 2 {
+			opts = append(opts, huh.NewOption(values[i], values[i+1]))
+		}
+		return opts
+	}
+
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Name of the application").
+				Value(&app.baseName).
+				Validate(func(name string) error {
+					for _, r := range name {
+						if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+							return errors.New("should contain only letters and digits")
+						}
+					}
+					if name == "" {
+						return errors.New("should not be empty")
+					}
+					if _, err := os.Stat(name); !os.IsNotExist(err) {
+						return fmt.Errorf("'%s' already exists", name)
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Package name").
+				Placeholder("com.mycompany.<name>").
+				Value(&app.packageName).
+				Validate(func(pkg string) error {
+					return validatePackageName(pkg, "java")
+				}),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Type of the application").
+				Options(options(
+					"Monolithic application", "monolith",
+					"Gateway application", "gateway",
+					"Microservice application", "microservice")...).
+				Value(&app.applicationType),
+		),
+		// HTTP sessions are only supported by monoliths.
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Authentication").
+				Options(options(
+					"JWT", "jwt",
+					"OAuth 2.0 / OIDC", "oauth2",
+					"HTTP session", "session")...).
+				Value(&app.authenticationType),
+		).WithHideFunc(func() bool { return app.applicationType != "monolith" }),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Authentication").
+				Options(options(
+					"JWT", "jwt",
+					"OAuth 2.0 / OIDC", "oauth2")...).
+				Value(&app.authenticationType),
+		).WithHideFunc(func() bool { return app.applicationType == "monolith" }),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Build tool").
+				Options(options("Maven", "maven", "Gradle", "gradle")...).
+				Value(&app.buildTool),
+			huh.NewSelect[string]().
+				Title("Production database").
+				Options(options(
+					"PostgreSQL", "postgresql",
+					"MySQL", "mysql",
+					"MariaDB", "mariadb",
+					"MongoDB", "mongodb")...).
+				Value(&app.prodDatabaseType),
+		),
+		// Only monoliths and gateways have a client.
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Client framework").
+				Options(options(
+					"Angular", "angular",
+					"React", "react",
+					"Vue", "vue",
+					"No client", "no")...).
+				Value(&app.clientFramework),
+		).WithHideFunc(func() bool { return app.applicationType == "microservice" }),
+	).WithTheme(huh.ThemeDracula())
+}
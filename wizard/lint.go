@@ -0,0 +1,150 @@
+package wizard
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// buildDependency is a dependency declared in a build file.
+type buildDependency struct {
+	coords string
+	// scope is the maven scope or the gradle configuration.
+	scope string
+}
+
+// conflictingStarters are the starters which should not be used
+// together.
+var conflictingStarters = []struct{ a, b, reason string }{
+	{"org.springframework.boot:spring-boot-starter-jetty", "org.springframework.boot:spring-boot-starter-undertow",
+		"only one embedded server can be used"},
+}
+
+// annotationProcessors are the dependencies which only work when
+// they are declared as annotation processors.
+var annotationProcessors = map[string]bool{
+	"org.projectlombok:lombok":                                     true,
+	"org.springframework.boot:spring-boot-configuration-processor": true,
+	"org.mapstruct:mapstruct-processor":                            true,
+	"org.hibernate.orm:hibernate-jpamodelgen":                      true,
+}
+
+// lintBuildFile analyzes the build file of the project and returns
+// the warnings about the duplicate dependencies, the conflicting
+// starters and the missing annotation processors.
+func lintBuildFile(dir string) ([]string, error) {
+	var (
+		deps       []buildDependency
+		processors map[string]bool
+		err        error
+	)
+	if b, e := os.ReadFile(filepath.Join(dir, "pom.xml")); e == nil {
+		deps, processors, err = parsePom(b)
+	} else if b, e := os.ReadFile(filepath.Join(dir, "build.gradle")); e == nil {
+		deps, processors = parseGradle(b)
+	} else if b, e := os.ReadFile(filepath.Join(dir, "build.gradle.kts")); e == nil {
+		deps, processors = parseGradle(b)
+	} else {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	seen := make(map[buildDependency]bool)
+	declared := make(map[string]bool)
+	for _, dep := range deps {
+		if seen[dep] {
+			warnings = append(warnings, fmt.Sprintf("%s is declared more than once", dep.coords))
+		}
+		seen[dep] = true
+		declared[dep.coords] = true
+	}
+
+	for _, c := range conflictingStarters {
+		if declared[c.a] && declared[c.b] {
+			_, a, _ := strings.Cut(c.a, ":")
+			_, b, _ := strings.Cut(c.b, ":")
+			warnings = append(warnings, fmt.Sprintf("%s and %s conflict, %s", a, b, c.reason))
+		}
+	}
+
+	// A nil set means the build tool picks the processors from the
+	// classpath.
+	if processors != nil {
+		var missing []string
+		for coords := range declared {
+			if annotationProcessors[coords] && !processors[coords] {
+				missing = append(missing, coords)
+			}
+		}
+		sort.Strings(missing)
+		for _, coords := range missing {
+			warnings = append(warnings, fmt.Sprintf("%s is not declared as an annotation processor", coords))
+		}
+	}
+	return warnings, nil
+}
+
+// parsePom returns the dependencies of the pom and the annotation
+// processors of the compiler plugin, or nil if it has none.
+func parsePom(b []byte) ([]buildDependency, map[string]bool, error) {
+	type artifact struct {
+		GroupId    string `xml:"groupId"`
+		ArtifactId string `xml:"artifactId"`
+		Scope      string `xml:"scope"`
+	}
+	var pom struct {
+		Dependencies []artifact `xml:"dependencies>dependency"`
+		Plugins      []struct {
+			ArtifactId string     `xml:"artifactId"`
+			Paths      []artifact `xml:"configuration>annotationProcessorPaths>path"`
+		} `xml:"build>plugins>plugin"`
+	}
+	if err := xml.Unmarshal(b, &pom); err != nil {
+		return nil, nil, fmt.Errorf("invalid pom.xml: %w", err)
+	}
+
+	var deps []buildDependency
+	for _, d := range pom.Dependencies {
+		deps = append(deps, buildDependency{d.GroupId + ":" + d.ArtifactId, d.Scope})
+	}
+	var processors map[string]bool
+	for _, p := range pom.Plugins {
+		if p.ArtifactId != "maven-compiler-plugin" || len(p.Paths) == 0 {
+			continue
+		}
+		processors = make(map[string]bool)
+		for _, path := range p.Paths {
+			processors[path.GroupId+":"+path.ArtifactId] = true
+		}
+	}
+	return deps, processors, nil
+}
+
+// gradleDependency matches a dependency in the groovy or the kotlin
+// dsl, e.g. implementation 'org.springframework.boot:spring-boot-starter'.
+var gradleDependency = regexp.MustCompile(
+	`(?m)^\s*(\w+)\s*\(?\s*["']([^:"'\s]+):([^:"'\s]+)(?::[^"']*)?["']`)
+
+// parseGradle returns the dependencies of the gradle build file and
+// the annotation processors.
+func parseGradle(b []byte) ([]buildDependency, map[string]bool) {
+	var deps []buildDependency
+	processors := make(map[string]bool)
+	for _, m := range gradleDependency.FindAllSubmatch(b, -1) {
+		scope, coords := string(m[1]), string(m[2])+":"+string(m[3])
+		switch scope {
+		case "annotationProcessor", "kapt", "testAnnotationProcessor":
+			processors[coords] = true
+		default:
+			deps = append(deps, buildDependency{coords, scope})
+		}
+	}
+	return deps, processors
+}
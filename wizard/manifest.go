@@ -0,0 +1,88 @@
+package wizard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// manifestFile is the file in the project directory which records
+// how the project was generated.
+const manifestFile = ".startspring.json"
+
+// manifest records how a project was generated so that it can be
+// verified or generated again later.
+type manifest struct {
+	// Server is empty if the project was generated offline from the
+	// bundled templates.
+	Server       string   `json:"server"`
+	Offline      bool     `json:"offline,omitempty"`
+	BootVersion  string   `json:"bootVersion"`
+	Dependencies []string `json:"dependencies"`
+	// AddOns are the files which were added or changed by the
+	// add-ons after the project was generated by the server.
+	AddOns []string `json:"addOns,omitempty"`
+
+	// Action and Params are the exact request of the project.
+	Action string     `json:"action"`
+	Params url.Values `json:"params"`
+}
+
+// newManifest returns the manifest of the project generated with
+// the given action of the server and changed by the add-ons. The
+// server is not recorded if the project was generated offline.
+func newManifest(server, action string, info *projectInfo, addOns []string, offline bool) manifest {
+	deps := info.dependencies
+	if deps == nil {
+		deps = []string{}
+	}
+	if offline {
+		server = ""
+	}
+	return manifest{
+		Server:       server,
+		Offline:      offline,
+		BootVersion:  info.bootVersion,
+		Dependencies: deps,
+		AddOns:       addOns,
+		Action:       action,
+		Params:       projectForm(info),
+	}
+}
+
+// writeManifest writes the manifest into the project directory.
+func writeManifest(dir string, mf manifest) error {
+	b, err := json.MarshalIndent(mf, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, manifestFile), append(b, '\n'), 0644)
+}
+
+// readManifest reads the manifest of the project directory.
+func readManifest(dir string) (*manifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s not found in %s, the project was not generated by startspring", manifestFile, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	mf := &manifest{}
+	if err := json.Unmarshal(b, mf); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", manifestFile, err)
+	}
+	return mf, nil
+}
+
+// checkServer checks that the project was generated by a server
+// which it can be generated again with.
+func (mf *manifest) checkServer() error {
+	if mf.Offline || mf.Server == "" {
+		return fmt.Errorf("the project was generated offline from the bundled templates, " +
+			"there is no server to generate it again with")
+	}
+	return nil
+}
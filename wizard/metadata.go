@@ -0,0 +1,81 @@
+package wizard
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// metadata is the metadata of the server with the additional fields
+// which the form renders.
+type metadata struct {
+	initializr.Metadata
+
+	// Extra holds the unknown fields which can be rendered
+	// in the form.
+	Extra []extraField `json:"-"`
+
+	// prefetch resolves the dependencies for the default boot
+	// version along with the metadata.
+	prefetch *depsPrefetch
+
+	// coordinates are the maven coordinates of the dependencies once
+	// they are prefetched, for the search of the dependencies.
+	coordinates map[string]string
+}
+
+// depsPrefetch is a fetch of the resolved dependencies which runs in
+// the background. deps can be read once done is closed.
+type depsPrefetch struct {
+	done chan struct{}
+	deps *initializr.Dependencies
+}
+
+// prefetchedDependencies returns the dependencies resolved for the
+// boot version if they were fetched along with the metadata. It waits
+// for the fetch to finish.
+func (m *metadata) prefetchedDependencies(bootVersion string) (*initializr.Dependencies, bool) {
+	if m.prefetch == nil || bootVersion != m.BootVersion.Default {
+		return nil, false
+	}
+	<-m.prefetch.done
+	return m.prefetch.deps, m.prefetch.deps != nil
+}
+
+func (m *metadata) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &m.Metadata); err != nil {
+		return err
+	}
+	m.Extra = extraFields(m.Unknown)
+	return nil
+}
+
+// extraFields returns the unknown fields of the metadata which can
+// be rendered in the form.
+func extraFields(unknown map[string]json.RawMessage) []extraField {
+	var extra []extraField
+	for id, raw := range unknown {
+		field := extraField{Id: id}
+		// Ignore the fields which can not be rendered.
+		if err := json.Unmarshal(raw, &field); err != nil {
+			continue
+		}
+		if field.Type == "text" || field.Type == "single-select" && len(field.Values) > 0 {
+			extra = append(extra, field)
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool {
+		return extra[i].Id < extra[j].Id
+	})
+	return extra
+}
+
+// extraField is an additional text or single-select field of
+// the metadata.
+type extraField struct {
+	Id      string `json:"-"`
+	Type    string
+	Default string
+	Values  []initializr.Value
+}
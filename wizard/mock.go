@@ -0,0 +1,426 @@
+package wizard
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// mockFixtures are the bundled responses of the mock server.
+//
+//go:embed mock
+var mockFixtures embed.FS
+
+// runMockServer runs the mock-server command which serves a
+// local Initializr from fixtures to test scripts against.
+func runMockServer(args []string) {
+	fs := flag.NewFlagSet("mock-server", flag.ExitOnError)
+	port := fs.Int("port", 8080, "port to listen on")
+	fixture := fs.String("fixture", "",
+		"directory of the fixtures which replace the bundled ones")
+	fs.Parse(args)
+
+	addr := fmt.Sprintf("localhost:%d", *port)
+	fmt.Printf("Serving the mock Initializr on http://%s\n", addr)
+	die(http.ListenAndServe(addr, &mockServer{dir: *fixture}))
+}
+
+// mockServer serves the files of the fixture directory by their
+// path, e.g. pom.xml for /pom.xml. The metadata is served from
+// metadata.json and the resolved dependencies from
+// dependencies.json. Missing fixtures fall back to the bundled
+// ones and projects are generated if there is no fixture.
+type mockServer struct {
+	dir string
+}
+
+func (s *mockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/", "/metadata/client":
+		s.serveJSON(w, r, "metadata.json", "application/vnd.initializr.v2.2+json")
+		return
+	case "/dependencies":
+		s.serveJSON(w, r, "dependencies.json", "application/vnd.initializr.v2.2+json")
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+	if b, err := s.fixture(name); err == nil {
+		w.Header().Set("Content-Type", contentType(name))
+		w.Write(b)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		mockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch name {
+	case "starter.zip":
+		s.serveProject(w, r)
+	case "pom.xml", "build.gradle":
+		b, err := s.buildFile(r, name)
+		if err != nil {
+			mockError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", contentType(name))
+		w.Write(b)
+	default:
+		mockError(w, http.StatusNotFound, "no fixture for "+r.URL.Path)
+	}
+}
+
+// fixture reads the fixture from the fixture directory or the
+// bundled ones.
+func (s *mockServer) fixture(name string) ([]byte, error) {
+	if s.dir != "" {
+		b, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(name)))
+		if err == nil || !os.IsNotExist(err) {
+			return b, err
+		}
+	}
+	return fs.ReadFile(mockFixtures, "mock/"+name)
+}
+
+// serveJSON serves the json fixture with the links pointing to
+// the mock server instead of start.spring.io.
+func (s *mockServer) serveJSON(w http.ResponseWriter, r *http.Request, name, mediaType string) {
+	b, err := s.fixture(name)
+	if err != nil {
+		mockError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	b = bytes.ReplaceAll(b, []byte(defaultServerURL), []byte("http://"+r.Host))
+	w.Header().Set("Content-Type", mediaType)
+	w.Write(b)
+}
+
+func (s *mockServer) serveProject(w http.ResponseWriter, r *http.Request) {
+	projectType := formValue(r, "type", "maven-project")
+	buildName := "pom.xml"
+	if strings.HasPrefix(projectType, "gradle") {
+		buildName = "build.gradle"
+	}
+	build, err := s.buildFile(r, buildName)
+	if err != nil {
+		mockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	name := formValue(r, "name", "demo")
+	artifact := formValue(r, "artifactId", "demo")
+	language := formValue(r, "language", "java")
+	pkg := formValue(r, "packageName", derivePackageName(formValue(r, "groupId", "com.example"), artifact, language))
+	baseDir := formValue(r, "baseDir", artifact)
+	ext := map[string]string{"java": ".java", "kotlin": ".kt", "groovy": ".groovy"}[language]
+	if ext == "" {
+		mockError(w, http.StatusBadRequest, "Unknown language '"+language+"'")
+		return
+	}
+	class := applicationClass(name)
+	srcDir := "src/main/" + language + "/" + strings.ReplaceAll(pkg, ".", "/")
+
+	files := []struct{ name, content string }{
+		{buildName, string(build)},
+		{"HELP.md", "# Getting Started\n"},
+		{srcDir + "/" + class + ext, mainClass(language, pkg, class)},
+		{"src/main/resources/application.properties",
+			"spring.application.name=" + name + "\n"},
+	}
+	// A war is started by the server it is deployed to.
+	if formValue(r, "packaging", "jar") == "war" {
+		files = append(files, struct{ name, content string }{
+			srcDir + "/ServletInitializer" + ext, servletInitializer(language, pkg, class)})
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		fw, err := zw.Create(path.Join(baseDir, f.name))
+		if err != nil {
+			mockError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		fw.Write([]byte(f.content))
+	}
+	if err := zw.Close(); err != nil {
+		mockError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+baseDir+`.zip"`)
+	w.Write(buf.Bytes())
+}
+
+// buildFile generates a minimal build file with the dependencies
+// of the request, which builds the language and the packaging of the
+// request.
+func (s *mockServer) buildFile(r *http.Request, name string) ([]byte, error) {
+	b, err := s.fixture("dependencies.json")
+	if err != nil {
+		return nil, err
+	}
+	deps := &initializr.Dependencies{}
+	if err := json.Unmarshal(b, deps); err != nil {
+		return nil, err
+	}
+
+	var coords []string
+	if ids := formValue(r, "dependencies", ""); ids != "" {
+		for _, id := range strings.Split(ids, ",") {
+			dep, ok := deps.Dependencies[id]
+			if !ok {
+				return nil, fmt.Errorf("Unknown dependency '%s'", id)
+			}
+			coords = append(coords, dep.GroupId+":"+dep.ArtifactId)
+		}
+	}
+
+	group := formValue(r, "groupId", "com.example")
+	artifact := formValue(r, "artifactId", "demo")
+	version := formValue(r, "version", "0.0.1-SNAPSHOT")
+	bootVersion := formValue(r, "bootVersion", "3.3.4")
+	javaVersion := formValue(r, "javaVersion", "17")
+	language := formValue(r, "language", "java")
+	war := formValue(r, "packaging", "jar") == "war"
+
+	// The libraries of the language.
+	starters := []string{"org.springframework.boot:spring-boot-starter"}
+	switch language {
+	case "kotlin":
+		starters = append(starters, "org.jetbrains.kotlin:kotlin-reflect")
+	case "groovy":
+		starters = append(starters, "org.apache.groovy:groovy")
+	}
+	coords = append(starters, coords...)
+	// The initializer of a war needs the servlet API of the web
+	// starter.
+	if war && !contains(coords, "org.springframework.boot:spring-boot-starter-web") {
+		coords = append(coords, "org.springframework.boot:spring-boot-starter-web")
+	}
+
+	var sb strings.Builder
+	if name == "build.gradle" {
+		sb.WriteString("plugins {\n")
+		switch language {
+		case "kotlin":
+			sb.WriteString("\tid 'org.jetbrains.kotlin.jvm' version '1.9.25'\n" +
+				"\tid 'org.jetbrains.kotlin.plugin.spring' version '1.9.25'\n")
+		case "groovy":
+			sb.WriteString("\tid 'groovy'\n")
+		default:
+			sb.WriteString("\tid 'java'\n")
+		}
+		if war {
+			sb.WriteString("\tid 'war'\n")
+		}
+		fmt.Fprintf(&sb, "\tid 'org.springframework.boot' version '%s'\n"+
+			"\tid 'io.spring.dependency-management' version '1.1.6'\n}\n\n", bootVersion)
+		fmt.Fprintf(&sb, "group = '%s'\nversion = '%s'\n\n", group, version)
+		fmt.Fprintf(&sb, "java {\n\ttoolchain {\n\t\tlanguageVersion = JavaLanguageVersion.of(%s)\n\t}\n}\n\n", javaVersion)
+		sb.WriteString("repositories {\n\tmavenCentral()\n}\n\n")
+		sb.WriteString("dependencies {\n")
+		for _, c := range coords {
+			fmt.Fprintf(&sb, "\timplementation '%s'\n", c)
+		}
+		if war {
+			sb.WriteString("\tprovidedRuntime 'org.springframework.boot:spring-boot-starter-tomcat'\n")
+		}
+		sb.WriteString("}\n")
+		if language == "kotlin" {
+			sb.WriteString("\nkotlin {\n\tcompilerOptions {\n\t\tfreeCompilerArgs.addAll '-Xjsr305=strict'\n\t}\n}\n")
+		}
+		return []byte(sb.String()), nil
+	}
+
+	sb.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<project>\n")
+	sb.WriteString("\t<modelVersion>4.0.0</modelVersion>\n")
+	fmt.Fprintf(&sb, "\t<parent>\n\t\t<groupId>org.springframework.boot</groupId>\n"+
+		"\t\t<artifactId>spring-boot-starter-parent</artifactId>\n\t\t<version>%s</version>\n\t</parent>\n", bootVersion)
+	fmt.Fprintf(&sb, "\t<groupId>%s</groupId>\n\t<artifactId>%s</artifactId>\n\t<version>%s</version>\n", group, artifact, version)
+	if war {
+		sb.WriteString("\t<packaging>war</packaging>\n")
+	}
+	fmt.Fprintf(&sb, "\t<properties>\n\t\t<java.version>%s</java.version>\n\t</properties>\n", javaVersion)
+	sb.WriteString("\t<dependencies>\n")
+	for _, c := range coords {
+		g, a, _ := strings.Cut(c, ":")
+		fmt.Fprintf(&sb, "\t\t<dependency>\n\t\t\t<groupId>%s</groupId>\n\t\t\t<artifactId>%s</artifactId>\n\t\t</dependency>\n", g, a)
+	}
+	if language == "kotlin" {
+		sb.WriteString("\t\t<dependency>\n\t\t\t<groupId>org.jetbrains.kotlin</groupId>\n" +
+			"\t\t\t<artifactId>kotlin-stdlib</artifactId>\n\t\t</dependency>\n")
+	}
+	if war {
+		sb.WriteString("\t\t<dependency>\n\t\t\t<groupId>org.springframework.boot</groupId>\n" +
+			"\t\t\t<artifactId>spring-boot-starter-tomcat</artifactId>\n\t\t\t<scope>provided</scope>\n\t\t</dependency>\n")
+	}
+	sb.WriteString("\t</dependencies>\n\t<build>\n")
+	if language == "kotlin" {
+		sb.WriteString("\t\t<sourceDirectory>${project.basedir}/src/main/kotlin</sourceDirectory>\n" +
+			"\t\t<testSourceDirectory>${project.basedir}/src/test/kotlin</testSourceDirectory>\n")
+	}
+	sb.WriteString("\t\t<plugins>\n\t\t\t<plugin>\n\t\t\t\t<groupId>org.springframework.boot</groupId>\n" +
+		"\t\t\t\t<artifactId>spring-boot-maven-plugin</artifactId>\n\t\t\t</plugin>\n")
+	switch language {
+	case "kotlin":
+		sb.WriteString(mavenKotlinPlugin)
+	case "groovy":
+		sb.WriteString(mavenGroovyPlugin)
+	}
+	sb.WriteString("\t\t</plugins>\n\t</build>\n</project>\n")
+	return []byte(sb.String()), nil
+}
+
+// mavenKotlinPlugin compiles the Kotlin sources with the classes of
+// Spring opened, as the Initializr configures it.
+const mavenKotlinPlugin = `			<plugin>
+				<groupId>org.jetbrains.kotlin</groupId>
+				<artifactId>kotlin-maven-plugin</artifactId>
+				<configuration>
+					<args>
+						<arg>-Xjsr305=strict</arg>
+					</args>
+					<compilerPlugins>
+						<plugin>spring</plugin>
+					</compilerPlugins>
+				</configuration>
+				<dependencies>
+					<dependency>
+						<groupId>org.jetbrains.kotlin</groupId>
+						<artifactId>kotlin-maven-allopen</artifactId>
+						<version>${kotlin.version}</version>
+					</dependency>
+				</dependencies>
+			</plugin>
+`
+
+// mavenGroovyPlugin compiles the Groovy sources.
+const mavenGroovyPlugin = `			<plugin>
+				<groupId>org.codehaus.gmavenplus</groupId>
+				<artifactId>gmavenplus-plugin</artifactId>
+				<version>3.0.2</version>
+				<executions>
+					<execution>
+						<goals>
+							<goal>addSources</goal>
+							<goal>addTestSources</goal>
+							<goal>generateStubs</goal>
+							<goal>compile</goal>
+							<goal>generateTestStubs</goal>
+							<goal>compileTests</goal>
+							<goal>removeStubs</goal>
+							<goal>removeTestStubs</goal>
+						</goals>
+					</execution>
+				</executions>
+			</plugin>
+`
+
+// applicationClass returns the name of the main class for the
+// project name the way the Initializr does, e.g. DemoApplication.
+func applicationClass(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	if sb.Len() == 0 || !unicode.IsLetter([]rune(sb.String())[0]) {
+		return "Application"
+	}
+	return sb.String() + "Application"
+}
+
+// mainClass returns the source of the main class of the project.
+func mainClass(language, pkg, class string) string {
+	switch language {
+	case "kotlin":
+		return fmt.Sprintf("package %s\n\n"+
+			"import org.springframework.boot.autoconfigure.SpringBootApplication\n"+
+			"import org.springframework.boot.runApplication\n\n"+
+			"@SpringBootApplication\nclass %s\n\n"+
+			"fun main(args: Array<String>) {\n\trunApplication<%s>(*args)\n}\n", pkg, class, class)
+	case "groovy":
+		return fmt.Sprintf("package %s\n\n"+
+			"import org.springframework.boot.SpringApplication\n"+
+			"import org.springframework.boot.autoconfigure.SpringBootApplication\n\n"+
+			"@SpringBootApplication\nclass %s {\n\n"+
+			"\tstatic void main(String[] args) {\n\t\tSpringApplication.run(%s, args)\n\t}\n\n}\n", pkg, class, class)
+	}
+	return fmt.Sprintf("package %s;\n\n"+
+		"import org.springframework.boot.SpringApplication;\n"+
+		"import org.springframework.boot.autoconfigure.SpringBootApplication;\n\n"+
+		"@SpringBootApplication\npublic class %s {\n\n"+
+		"\tpublic static void main(String[] args) {\n\t\tSpringApplication.run(%s.class, args);\n\t}\n\n}\n", pkg, class, class)
+}
+
+// servletInitializer returns the source of the class which starts
+// the application of a war in a servlet container.
+func servletInitializer(language, pkg, class string) string {
+	const imports = "import org.springframework.boot.builder.SpringApplicationBuilder\n" +
+		"import org.springframework.boot.web.servlet.support.SpringBootServletInitializer\n"
+	switch language {
+	case "kotlin":
+		return fmt.Sprintf("package %s\n\n%s\nclass ServletInitializer : SpringBootServletInitializer() {\n\n"+
+			"\toverride fun configure(application: SpringApplicationBuilder): SpringApplicationBuilder {\n"+
+			"\t\treturn application.sources(%s::class.java)\n\t}\n\n}\n", pkg, imports, class)
+	case "groovy":
+		return fmt.Sprintf("package %s\n\n%s\nclass ServletInitializer extends SpringBootServletInitializer {\n\n"+
+			"\t@Override\n\tprotected SpringApplicationBuilder configure(SpringApplicationBuilder application) {\n"+
+			"\t\tapplication.sources(%s)\n\t}\n\n}\n", pkg, imports, class)
+	}
+	return fmt.Sprintf("package %s;\n\n%s\npublic class ServletInitializer extends SpringBootServletInitializer {\n\n"+
+		"\t@Override\n\tprotected SpringApplicationBuilder configure(SpringApplicationBuilder application) {\n"+
+		"\t\treturn application.sources(%s.class);\n\t}\n\n}\n", pkg, strings.ReplaceAll(imports, "\n", ";\n"), class)
+}
+
+func formValue(r *http.Request, key, def string) string {
+	if v := strings.TrimSpace(r.Form.Get(key)); v != "" {
+		return v
+	}
+	return def
+}
+
+func contentType(name string) string {
+	switch path.Ext(name) {
+	case ".zip":
+		return "application/zip"
+	case ".json":
+		return "application/json"
+	case ".xml":
+		return "application/xml"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+func mockError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  status,
+		"error":   http.StatusText(status),
+		"message": msg,
+	})
+}
@@ -0,0 +1,1391 @@
+package wizard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+var hintStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#6272a4"))
+
+type state int
+
+const (
+	statePreset state = iota
+	stateForm
+	stateReview
+	stateSpinner
+	stateExplore
+	stateDone
+)
+
+type errMsg struct{ err error }
+
+// hooks are the callbacks of a model which is embedded in another
+// program.
+type hooks struct {
+	// submit is called with the answers once the form is completed.
+	submit func(info *projectInfo) tea.Cmd
+	// exit is called instead of quitting the program.
+	exit func(m model, canceled bool) tea.Cmd
+	// skipGeneration ends the model once the form is completed.
+	skipGeneration bool
+}
+
+// generatedMsg is sent after the project is generated with
+// the notes and the warnings about the generated build file.
+type generatedMsg struct {
+	notes    []string
+	warnings []string
+	// buildFile is the path of the build file if only the build
+	// file is generated.
+	buildFile string
+	// files are the slash separated paths of the files written into
+	// the project directory.
+	files []string
+}
+
+// errCanceled is the error of a generation which was canceled.
+var errCanceled = errors.New("canceled, nothing was left of the project")
+
+// downloadedMsg is sent after the project archive is downloaded
+// when it has to be explored before extraction.
+type downloadedMsg struct{ archive *archive }
+
+// noticeMsg is a short message shown to the user below
+// the current view.
+type noticeMsg string
+
+// model contains the program's state and implements
+// tea.Model.
+type model struct {
+	state      state
+	client     *initializr.Client
+	info       *projectInfo
+	data       *metadata
+	opts       options
+	finalMsg   string
+	warnings   []string
+	notice     string
+	projectDir string
+	// buildFile is the path of the build file if only the build
+	// file is generated.
+	buildFile string
+	// files are the files which the generation wrote into the
+	// project directory.
+	files      []string
+	startedAt  time.Time
+	isQuitting bool
+	isFinished bool
+	hasExited  bool
+	err        error
+	hooks      hooks
+
+	// confirmingQuit is set while the user is asked whether the
+	// answers of the form should be discarded.
+	confirmingQuit bool
+
+	// catalogProjects are the selected projects of the Spring CLI
+	// catalogs which are applied after extraction.
+	catalogProjects []catalogProject
+
+	// reviewCursor is the selected section of the review screen.
+	reviewCursor int
+
+	// editors are the detected editors which the user chooses from
+	// to open the generated project.
+	editors []editor
+
+	// estimatedSize is the expected size of the project archive,
+	// 0 if it is unknown.
+	estimatedSize int64
+	// preflight holds the warnings about the local tools which are
+	// missing to build, run or push the project.
+	preflight []string
+
+	// progress receives the progress of the running download, and
+	// downloaded and downloadSize hold the last one.
+	progress     chan progressMsg
+	downloaded   int64
+	downloadSize int64
+	bar          progress.Model
+
+	// ctx is the context of the running generation, which cancel
+	// cancels. cancelTo is the state to go to once it is canceled,
+	// stateDone to quit.
+	ctx      context.Context
+	cancel   context.CancelFunc
+	cancelTo state
+	// canceling is set while the canceled generation removes what
+	// it extracted.
+	canceling bool
+
+	// presetForm asks for the saved profile which fills in the
+	// answers of the form, with its name in preset.
+	presetForm *huh.Form
+	preset     *string
+
+	form     *huh.Form
+	deps     *depPicker
+	spinner  spinner.Model
+	explorer explorer
+	archive  *archive
+	width    int
+	height   int
+
+	// previewing is set while the build file is shown in the
+	// preview pager over the form.
+	previewing     bool
+	previewName    string
+	previewContent string
+	preview        viewport.Model
+}
+
+func newModel(data *metadata, client *initializr.Client, info *projectInfo, opts options) model {
+	form, deps := newForm(info, data, opts)
+	m := model{
+		state:   stateForm,
+		client:  client,
+		info:    info,
+		data:    data,
+		opts:    opts,
+		form:    form,
+		deps:    deps,
+		spinner: newSpinner(),
+		bar:     newProgressBar(),
+		ctx:     context.Background(),
+	}
+	if len(opts.profiles) > 0 {
+		m.state = statePreset
+		m.preset = new(string)
+		m.presetForm = newPresetForm(profileNames(opts.profiles), m.preset, opts)
+	}
+	return m
+}
+
+func (m model) Init() tea.Cmd {
+	if m.state == statePreset {
+		return tea.Batch(m.presetForm.Init(), coordinatesCmd(m.data))
+	}
+	return tea.Batch(m.form.Init(), coordinatesCmd(m.data))
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.hasExited {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// The running generation is canceled first so that it does
+		// not leave any file, a second ctrl+c quits right away.
+		if m.state == stateSpinner && !m.canceling && m.cancel != nil &&
+			(msg.String() == "ctrl+c" || msg.String() == "esc") {
+			m.canceling = true
+			m.cancelTo = stateReview
+			if msg.String() == "ctrl+c" {
+				m.cancelTo = stateDone
+			}
+			m.cancel()
+			return m, nil
+		}
+		if msg.String() == "ctrl+c" {
+			// Ask before the answers of the form are lost.
+			if m.state == stateForm && !m.confirmingQuit && m.hasChanges() {
+				m.confirmingQuit = true
+				m.previewing = false
+				return m, nil
+			}
+			m.isQuitting = true
+			return m, m.quit(true)
+		}
+	case noticeMsg:
+		m.notice = string(msg)
+		return m, nil
+	case preflightMsg:
+		m.preflight = msg.warnings
+		return m, nil
+	case sizeMsg:
+		m.estimatedSize = msg.size
+		return m, nil
+	case coordinatesMsg:
+		// The forms created later take them from the metadata.
+		m.data.coordinates = msg
+		m.deps.coordinates = msg
+		m.deps.applyFilter()
+		return m, nil
+	case progressMsg:
+		m.downloaded, m.downloadSize = msg.read, msg.total
+		return m, waitProgress(m.progress)
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	}
+
+	switch m.state {
+	case statePreset:
+
+		form, cmd := m.presetForm.Update(msg)
+		if f, ok := form.(*huh.Form); ok {
+			m.presetForm = f
+		}
+		if m.presetForm.State != huh.StateCompleted {
+			return m, cmd
+		}
+		// The fields of the form are bound to the answers when it is
+		// created, so it is created again with the ones of the profile.
+		if cfg, ok := m.opts.profiles[*m.preset]; ok {
+			m.info.fill(cfg.projectInfo())
+			m.form, m.deps = newForm(m.info, m.data, m.opts)
+		}
+		m.state = stateForm
+		return m, m.form.Init()
+
+	case stateForm:
+
+		if msg, ok := msg.(tea.KeyMsg); ok && m.confirmingQuit {
+			switch msg.String() {
+			case "y":
+				m.isQuitting = true
+				return m, m.quit(true)
+			case "s":
+				if err := writeConfig(sessionFile, m.info); err != nil {
+					m.notice = err.Error()
+					m.confirmingQuit = false
+					return m, nil
+				}
+				m.finalMsg = fmt.Sprintf("Saved the answers to %s, continue with startspring -resume %s",
+					sessionFile, sessionFile)
+				m.state = stateDone
+				m.isFinished = true
+				return m, m.quit(true)
+			case "n", "esc":
+				m.confirmingQuit = false
+			}
+			return m, nil
+		}
+
+		if msg, ok := msg.(previewMsg); ok {
+			m.notice = ""
+			m.previewing = true
+			m.previewName = msg.name
+			m.previewContent = msg.content
+			m.preview = newPreview(msg.content, m.width, m.height)
+			return m, nil
+		}
+
+		if m.previewing {
+			if msg, ok := msg.(tea.WindowSizeMsg); ok {
+				m.preview = newPreview(m.previewContent, msg.Width, msg.Height)
+				return m, nil
+			}
+			if msg, ok := msg.(tea.KeyMsg); ok && (isQuitKey(msg) || msg.String() == "ctrl+b") {
+				m.previewing = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.preview, cmd = m.preview.Update(msg)
+			return m, cmd
+		}
+
+		// Let the user continue in the web UI with the current
+		// selections.
+		if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "ctrl+o" {
+			return m, openInBrowser(m.client.URL, m.info)
+		}
+
+		// Preview the build file with the dependencies picked so
+		// far.
+		if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "ctrl+b" &&
+			m.deps.focused && !m.deps.filtering {
+			m.notice = "Fetching the build file..."
+			return m, previewCmd(m.client, m.data, m.info.clone())
+		}
+
+		form, cmd := m.form.Update(msg)
+		if f, ok := form.(*huh.Form); ok {
+			m.form = f
+		}
+		m.refilterDeps()
+		if missing := m.deps.applyBundles(m.opts.bundles); len(missing) > 0 {
+			m.notice = "Skipped the unavailable dependencies of the bundle: " + strings.Join(missing, ", ")
+		}
+
+		if m.form.State == huh.StateCompleted {
+			m.info.dependencies, m.catalogProjects = splitCatalog(m.info.dependencies, m.opts.catalog)
+		}
+
+		if m.form.State == huh.StateCompleted && m.opts.share {
+			m.state = stateDone
+			text, err := shareText(m.client.URL, m.info, m.data)
+			if err != nil {
+				text = err.Error()
+			}
+			m.finalMsg = text
+			return m, m.quit(false)
+		}
+
+		if m.form.State == huh.StateCompleted && m.opts.web {
+			m.state = stateDone
+			m.finalMsg = fmt.Sprintf("Opened %s", shareURL(m.client.URL, m.info))
+			if err := openBrowser(shareURL(m.client.URL, m.info)); err != nil {
+				m.finalMsg = err.Error()
+			}
+			m.finalMsg += m.shareQR()
+			return m, m.quit(false)
+		}
+
+		// The embedding program generates the project itself.
+		if m.form.State == huh.StateCompleted && m.hooks.skipGeneration {
+			applyDefaults(m.info, m.data)
+			m.state = stateDone
+			m.isFinished = true
+			return m, tea.Batch(m.submit(), m.quit(false))
+		}
+
+		// Let the user check the answers before the project is
+		// generated.
+		if m.form.State == huh.StateCompleted {
+			return m.review()
+		}
+		return m, cmd
+
+	case stateReview:
+
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return m.updateReview(msg)
+		}
+		return m, nil
+
+	case stateSpinner:
+
+		switch msg := msg.(type) {
+		case downloadedMsg:
+			if m.canceling {
+				msg.archive.remove()
+				return m.Update(errMsg{errCanceled})
+			}
+			e, err := newExplorer(msg.archive)
+			if err != nil {
+				msg.archive.remove()
+				m.err = err
+				m.finalMsg = err.Error()
+				m.state = stateDone
+				return m, nil
+			}
+			if m.width > 0 {
+				e.setSize(m.width, m.height)
+			}
+			m.explorer = e
+			m.archive = msg.archive
+			m.state = stateExplore
+			return m, nil
+
+		case generatedMsg:
+			// It was done before it could be canceled.
+			m.canceling = false
+			if msg.buildFile != "" {
+				m.finalMsg = "Build file written to " + msg.buildFile
+				m.projectDir, _ = filepath.Abs(filepath.Dir(msg.buildFile))
+				m.buildFile = msg.buildFile
+				m.state = stateDone
+				return m, m.openCmd()
+			}
+			m.finalMsg = "Project generated successfully!"
+			if m.opts.offline {
+				m.finalMsg += "\n" + warnStyle.Render(
+					"Generated offline from the bundled templates, review the build file before use")
+			}
+			for _, note := range msg.notes {
+				m.finalMsg += "\n" + note
+			}
+			m.finalMsg += m.shareQR()
+			m.warnings = append(m.warnings, msg.warnings...)
+			m.projectDir, _ = filepath.Abs(m.info.dir())
+			m.files = msg.files
+			m.notice = ""
+			m.state = stateDone
+			if time.Since(m.startedAt) > notifyAfter {
+				return m, tea.Batch(notifyCmd(nil), m.openCmd())
+			}
+			return m, m.openCmd()
+
+		case errMsg:
+			if m.canceling && m.cancelTo == stateReview {
+				m.canceling = false
+				m.warnings = nil
+				m.startedAt = time.Time{}
+				m.notice = "Canceled the generation"
+				m.state = stateReview
+				return m, nil
+			}
+			m.err = msg.err
+			m.finalMsg = msg.err.Error()
+			m.state = stateDone
+			if m.canceling {
+				m.isFinished = true
+				return m, m.quit(true)
+			}
+			if time.Since(m.startedAt) > notifyAfter {
+				return m, notifyCmd(msg.err)
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case stateExplore:
+
+		if msg, ok := msg.(tea.KeyMsg); ok && !m.explorer.viewing {
+			switch msg.String() {
+			case "x":
+				applyDefaults(m.info, m.data)
+				m.state = stateSpinner
+				m.startCancelable()
+				// The project is not submitted yet if it is
+				// previewed from the review.
+				if m.startedAt.IsZero() {
+					m.startedAt = time.Now()
+					return m, tea.Batch(m.spin(m.extractProject(m.archive)), m.submit())
+				}
+				return m, m.spin(m.extractProject(m.archive))
+			case "b":
+				m.archive.remove()
+				m.archive = nil
+				m.warnings = nil
+				m.startedAt = time.Time{}
+				m.state = stateReview
+				return m, nil
+			case "q", "esc":
+				m.archive.remove()
+				m.finalMsg = "Aborted, the project was not extracted"
+				m.state = stateDone
+				return m, m.quit(true)
+			}
+		}
+		var cmd tea.Cmd
+		m.explorer, cmd = m.explorer.Update(msg)
+		return m, cmd
+
+	default:
+		keyMsg, ok := msg.(tea.KeyMsg)
+		if ok && len(m.editors) > 0 {
+			return m.chooseEditor(keyMsg)
+		}
+		if m.projectDir == "" || ok && isQuitKey(keyMsg) {
+			m.isFinished = true
+			return m, m.quit(false)
+		}
+		if !ok {
+			return m, nil
+		}
+
+		switch keyMsg.String() {
+		case "o":
+			editors := detectEditors(m.opts.editors)
+			switch len(editors) {
+			case 0:
+				m.notice = "No editor found, install code or idea, set $EDITOR or add one to editors.yaml"
+				return m, nil
+			case 1:
+				return m, openEditorCmd(editors[0], m.projectDir)
+			}
+			if len(editors) > 9 {
+				editors = editors[:9]
+			}
+			m.editors = editors
+			return m, nil
+		case "p":
+			return m, copyCmd(m.projectDir)
+		case "r":
+			return m, copyCmd(runCommand(m.projectDir, m.info.projectType))
+		case "u":
+			return m, copyCmd(shareURL(m.client.URL, m.info))
+		case "c":
+			action, err := m.data.generateAction(m.info.projectType)
+			if err != nil {
+				m.notice = err.Error()
+				return m, nil
+			}
+			return m, copyCmd(curlCommand(m.client.URL, m.info, action))
+		case "n":
+			return m.another()
+		}
+		return m, nil
+	}
+}
+
+// openCmd opens the generated project in the editor of -open.
+func (m model) openCmd() tea.Cmd {
+	if m.opts.open == "" {
+		return nil
+	}
+	e, err := findEditor(m.opts.open, m.opts.editors)
+	if err != nil {
+		return func() tea.Msg { return noticeMsg(err.Error()) }
+	}
+	return openEditorCmd(e, m.projectDir)
+}
+
+// chooseEditor opens the project in the editor of the pressed number,
+// or goes back to the other actions.
+func (m model) chooseEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	editors := m.editors
+	m.editors = nil
+	if n, err := strconv.Atoi(msg.String()); err == nil && n >= 1 && n <= len(editors) {
+		return m, openEditorCmd(editors[n-1], m.projectDir)
+	}
+	return m, nil
+}
+
+// another goes back to the form to generate another project, starting
+// with the answers of the last one. The metadata is not fetched again.
+func (m model) another() (tea.Model, tea.Cmd) {
+	for _, p := range m.catalogProjects {
+		m.info.dependencies = append(m.info.dependencies, catalogPrefix+p.Name)
+	}
+	m.catalogProjects = nil
+	m.finalMsg, m.notice, m.warnings, m.preflight = "", "", nil, nil
+	m.projectDir, m.buildFile, m.files = "", "", nil
+	m.startedAt = time.Time{}
+	m.archive = nil
+	m.estimatedSize = 0
+	m.reviewCursor = 0
+
+	m.form, m.deps = newForm(m.info, m.data, m.opts)
+	m.state = stateForm
+	return m, m.form.Init()
+}
+
+// refilterDeps rebuilds the dependencies of the form once the boot
+// version or the facets are answered, and tells which of the selected
+// dependencies it removes.
+func (m *model) refilterDeps() {
+	bootVersion := m.info.bootVersion
+	if bootVersion == "" {
+		bootVersion = m.data.BootVersion.Default
+	}
+	if removed := m.deps.refilter(bootVersion); len(removed) > 0 {
+		m.notice = fmt.Sprintf("Removed %s, not compatible with Spring Boot %s",
+			strings.Join(removed, ", "), bootVersion)
+	}
+}
+
+// review shows the answers of the form before the project is
+// generated, along with the estimated size of its archive and the
+// warnings about the missing local tools.
+func (m model) review() (tea.Model, tea.Cmd) {
+	m.notice = ""
+	m.estimatedSize = 0
+	m.preflight = nil
+	m.state = stateReview
+	return m, tea.Batch(estimateSizeCmd(m.client, m.data, m.info.clone()),
+		preflightCmd(m.data, m.info.clone(), m.opts.push != ""))
+}
+
+// generate starts the spinner and generates the project.
+func (m model) generate() (tea.Model, tea.Cmd) {
+	m.state = stateSpinner
+	m.startCancelable()
+	m.startedAt = time.Now()
+	wait := m.trackProgress()
+	return m, tea.Batch(m.spin(m.generateProject()), wait, m.submit())
+}
+
+// failed returns the message of the error of the generation, which
+// is errCanceled if it was canceled.
+func (m model) failed(err error) errMsg {
+	if m.ctx.Err() != nil {
+		return errMsg{errCanceled}
+	}
+	return errMsg{err}
+}
+
+// startCancelable gives the commands which are started next a context
+// which esc and ctrl+c cancel.
+func (m *model) startCancelable() {
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.canceling = false
+}
+
+// trackProgress makes the next download report its progress and
+// returns the command which waits for it.
+func (m *model) trackProgress() tea.Cmd {
+	m.progress = make(chan progressMsg, 1)
+	m.downloaded, m.downloadSize = 0, 0
+	return waitProgress(m.progress)
+}
+
+// reportProgress returns the report function of the download, nil
+// if its progress is not tracked.
+func (m model) reportProgress() func(progressMsg) {
+	if m.progress == nil {
+		return nil
+	}
+	return reportTo(m.progress)
+}
+
+// doneProgress stops waiting for the progress of the download.
+func (m model) doneProgress() {
+	if m.progress != nil {
+		close(m.progress)
+	}
+}
+
+// quit ends the model. The program is quit unless the model is
+// embedded in another program.
+func (m *model) quit(canceled bool) tea.Cmd {
+	// The archive of an explored project is not extracted any more.
+	m.archive.remove()
+	if m.hooks.exit == nil {
+		return tea.Quit
+	}
+	m.hasExited = true
+	return m.hooks.exit(*m, canceled)
+}
+
+// submit calls the submit hook with a copy of the answers where the
+// empty ones are filled with the defaults.
+func (m model) submit() tea.Cmd {
+	if m.hooks.submit == nil {
+		return nil
+	}
+	info := m.info.clone()
+	applyDefaults(info, m.data)
+	return m.hooks.submit(info)
+}
+
+// hasChanges reports whether the user has answered any question
+// of the form with a value other than the default.
+func (m model) hasChanges() bool {
+	info, data := m.info, m.data
+	texts := []string{info.name, info.group, info.artifact, info.version, info.description}
+	for _, text := range texts {
+		if strings.TrimSpace(text) != "" {
+			return true
+		}
+	}
+	if len(info.dependencies) > 0 {
+		return true
+	}
+
+	selects := []struct{ val, def string }{
+		{info.language, data.Language.Default},
+		{info.javaVersion, data.JavaVersion.Default},
+		{info.bootVersion, data.BootVersion.Default},
+		{info.projectType, data.ProjectType.Default},
+		{info.packaging, data.Packaging.Default},
+	}
+	for _, field := range data.Extra {
+		if val, ok := info.extra[field.Id]; ok {
+			selects = append(selects, struct{ val, def string }{*val, field.Default})
+		}
+	}
+	for _, s := range selects {
+		if s.val != "" && s.val != s.def {
+			return true
+		}
+	}
+	return false
+}
+
+func isQuitKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "q", "esc", "enter":
+		return true
+	}
+	return false
+}
+
+func (m model) View() string {
+	if m.isQuitting {
+		return ""
+	}
+	switch m.state {
+	case statePreset:
+		return m.presetForm.View()
+	case stateForm:
+		if m.previewing {
+			return fmt.Sprintf("%s\n\n%s\n%s", titleStyle.Render(m.previewName), m.preview.View(),
+				hintStyle.Render(fmt.Sprintf("↑/↓ scroll • esc back to the form • %3.f%%", m.preview.ScrollPercent()*100)))
+		}
+		if m.confirmingQuit {
+			return m.form.View() + "\n" + warnStyle.Render("Discard and quit?") + " " +
+				hintStyle.Render("y discard • s save the answers and quit • n back to the form")
+		}
+		notice := m.notice
+		if notice == "" {
+			notice = "ctrl+o open in start.spring.io"
+			if m.deps.focused {
+				notice += " • ctrl+b preview build file"
+			}
+		}
+		if m.opts.offline {
+			return m.form.View() + "\n" + warnStyle.Render("Offline mode, only the bundled templates are available") +
+				"\n" + hintStyle.Render(notice)
+		}
+		return m.form.View() + "\n" + hintStyle.Render(notice)
+	case stateReview:
+		return m.reviewView()
+	case stateSpinner:
+		if m.cancel == nil || m.canceling {
+			return m.progressView()
+		}
+		return m.progressView() + "\n" + hintStyle.Render("esc cancel • ctrl+c cancel and quit")
+	case stateExplore:
+		return m.explorer.View()
+	default:
+		finalMsg := m.finalMsg
+		if m.projectDir != "" {
+			for _, w := range append(m.preflight, m.warnings...) {
+				finalMsg += "\n" + warnStyle.Render("! "+w)
+			}
+		}
+		if m.projectDir == "" || m.isFinished {
+			return fmt.Sprintf("%s\n", finalMsg)
+		}
+		hint := "o open in editor • p copy path • r copy run command • u copy start.spring.io url • c copy curl command • n new project • q quit"
+		if len(m.editors) > 0 {
+			var choices []string
+			for i, e := range m.editors {
+				choices = append(choices, fmt.Sprintf("%d %s", i+1, e.name))
+			}
+			hint = "Open in " + strings.Join(choices, " • ") + " • esc back"
+		} else if m.notice != "" {
+			hint = m.notice
+		}
+		return fmt.Sprintf("%s\n\n%s\n", finalMsg, hintStyle.Render(hint))
+	}
+}
+
+// spin runs the command while showing the spinner.
+func (m model) spin(cmd tea.Cmd) tea.Cmd {
+	if m.opts.plain {
+		return cmd
+	}
+	return tea.Batch(m.spinner.Tick, cmd)
+}
+
+func (m model) generateProject() tea.Cmd {
+	return func() tea.Msg {
+		applyDefaults(m.info, m.data)
+		if m.data.isBuildType(m.info.projectType) {
+			m.doneProgress()
+			p, err := writeBuildFile(m.ctx, m.client, m.data, m.info, m.opts.existing)
+			if err != nil {
+				return m.failed(err)
+			}
+			return generatedMsg{buildFile: p}
+		}
+		a, err := download(m.ctx, m.client, m.data, m.info, m.reportProgress())
+		m.doneProgress()
+		if err != nil {
+			return m.failed(err)
+		}
+		if m.opts.explore {
+			return downloadedMsg{a}
+		}
+		return m.extractProject(a)()
+	}
+}
+
+// previewProject downloads the project to be explored. The answers
+// are kept as they are, so that they can still be edited.
+func (m model) previewProject() tea.Cmd {
+	return func() tea.Msg {
+		info := m.info.clone()
+		applyDefaults(info, m.data)
+		a, err := download(m.ctx, m.client, m.data, info, m.reportProgress())
+		m.doneProgress()
+		if err != nil {
+			return m.failed(err)
+		}
+		return downloadedMsg{a}
+	}
+}
+
+// download downloads the project archive into a temporary file. The
+// body is written as it arrives and its progress is reported unless
+// report is nil.
+func download(ctx context.Context, client *initializr.Client, data *metadata, info *projectInfo, report func(progressMsg)) (*archive, error) {
+	action, err := data.Action(info.projectType, "project")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := getProjectFile(ctx, client, action, info)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.New("failed to generate project")
+	}
+
+	var r io.Reader = resp.Body
+	if report != nil {
+		total := resp.ContentLength
+		if total < 0 {
+			total = 0
+		}
+		r = &progressReader{r: resp.Body, total: total, report: report}
+	}
+	a, err := newArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	// The size only improves the next estimates.
+	recordSize(info, a.size)
+	return a, nil
+}
+
+// extractProject extracts the downloaded project archive into
+// the project directory and removes the archive.
+func (m model) extractProject(a *archive) tea.Cmd {
+	return func() tea.Msg {
+		var addOns, warnings []string
+		finish := func(dir string) (err error) {
+			addOns, warnings, err = m.finishProject(dir)
+			return err
+		}
+		extracted, err := unzip(m.ctx, a, m.info, m.opts, finish)
+		a.remove()
+		if err != nil {
+			return m.failed(err)
+		}
+		files := append(append(extracted, addOns...), manifestFile)
+		msg := generatedMsg{notes: m.buildNotes(), files: unique(files)}
+		if w := longPathWarning(m.info.dir()); w != "" {
+			msg.warnings = append(msg.warnings, w)
+		}
+		if w := artifactWarning(m.info.artifact); w != "" {
+			msg.warnings = append(msg.warnings, w)
+		}
+		if len(addOns) > 0 {
+			msg.notes = append(msg.notes, "Added by the add-ons: "+strings.Join(addOns, ", "))
+		}
+		msg.warnings = append(msg.warnings, warnings...)
+		// A failed push keeps the project, which can still be pushed
+		// by hand.
+		if m.opts.push != "" {
+			u, err := m.push()
+			if err != nil {
+				msg.warnings = append(msg.warnings, "Failed to push the project: "+err.Error())
+			} else {
+				msg.notes = append(msg.notes, "Pushed to "+u)
+			}
+		}
+		return msg
+	}
+}
+
+// push creates the repository of the project and pushes it with -push.
+// The offline client only serves the bundled templates, so the api of
+// the git host is reached with a client of the http options then.
+func (m model) push() (string, error) {
+	client := m.client.HTTPClient
+	if m.opts.offline {
+		c, err := newClient(m.opts.http)
+		if err != nil {
+			return "", err
+		}
+		client = c.HTTPClient
+	}
+	return pushProject(m.ctx, client, m.info.dir(), m.info, m.opts.push, m.opts.repo, m.opts.public)
+}
+
+// finishProject runs the steps which follow the extraction on the
+// project in the directory: the add-ons, the manifest, the catalog
+// projects and the lint. It returns the files added by the add-ons
+// and the warnings of the catalog and the lint. A failed step or a
+// canceled context stops it, and unzip then removes the project.
+func (m model) finishProject(dir string) (addOns, warnings []string, err error) {
+	if addOns, err = applyAddOns(dir, m.info); err != nil {
+		return nil, nil, err
+	}
+	if err := m.ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if err := m.recordRequest(dir, addOns); err != nil {
+		return nil, nil, err
+	}
+	if err := m.ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	// The catalog projects change the build file, so they are
+	// applied before it is linted.
+	warnings = applyCatalog(dir, m.catalogProjects)
+	if err := m.ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if m.opts.lint {
+		lint, err := lintBuildFile(dir)
+		if err != nil {
+			lint = []string{"Failed to lint the build file: " + err.Error()}
+		}
+		warnings = append(warnings, lint...)
+	}
+	// The files are normalized once the last of them is written.
+	if m.opts.reproducible {
+		if err := m.ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		if err := normalizeTree(dir); err != nil {
+			return nil, nil, err
+		}
+	}
+	return addOns, warnings, nil
+}
+
+// recordRequest writes the manifest into the project directory.
+func (m model) recordRequest(dir string, addOns []string) error {
+	action, err := m.data.Action(m.info.projectType, "project")
+	if err != nil {
+		return err
+	}
+	return writeManifest(dir, newManifest(m.client.URL, action, m.info, addOns, m.opts.offline))
+}
+
+// buildNotes returns the notes about the BOMs and the repositories
+// which the selected dependencies add to the build file. As these
+// are only informative, failing to resolve them is not an error.
+func (m model) buildNotes() []string {
+	if _, ok := m.data.Links.First("dependencies"); !ok || len(m.info.dependencies) == 0 {
+		return nil
+	}
+	deps, ok := m.data.prefetchedDependencies(m.info.bootVersion)
+	if !ok {
+		var err error
+		if deps, err = getDependencies(m.client, m.info.bootVersion); err != nil {
+			return nil
+		}
+	}
+
+	var notes []string
+	boms, repos := extras(deps, m.info.dependencies)
+	if len(boms) > 0 {
+		notes = append(notes, "Imported BOMs: "+strings.Join(boms, ", "))
+	}
+	if len(repos) > 0 {
+		notes = append(notes, "Added repositories: "+strings.Join(repos, ", "))
+	}
+	return notes
+}
+
+// shareQR returns the QR code of the start.spring.io url of the
+// project if it is asked for.
+func (m model) shareQR() string {
+	if !m.opts.qr {
+		return ""
+	}
+	code, err := qrCode(shareURL(m.client.URL, m.info))
+	if err != nil {
+		return "\n" + err.Error()
+	}
+	return "\n\nScan to open the configuration in start.spring.io\n\n" + code
+}
+
+// openInBrowser opens the server, e.g. start.spring.io, with the
+// current selections of the project.
+func openInBrowser(server string, info *projectInfo) tea.Cmd {
+	return func() tea.Msg {
+		if err := openBrowser(shareURL(server, info)); err != nil {
+			return noticeMsg(err.Error())
+		}
+		return noticeMsg("Opened start.spring.io in your browser")
+	}
+}
+
+// notifyCmd notifies the user that the generation is complete.
+func notifyCmd(err error) tea.Cmd {
+	return func() tea.Msg {
+		if err != nil {
+			notify("startspring", err.Error())
+		} else {
+			notify("startspring", "Project generated successfully!")
+		}
+		return nil
+	}
+}
+
+// copyCmd copies the text to the clipboard.
+func copyCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		if err := copyToClipboard(text); err != nil {
+			return noticeMsg(err.Error())
+		}
+		return noticeMsg(fmt.Sprintf("Copied %s", text))
+	}
+}
+
+// newForm returns the form of the project and its dependency picker.
+func newForm(info *projectInfo, data *metadata, opts options) (*huh.Form, *depPicker) {
+	validate := func(str string) error {
+		str = strings.TrimSpace(str)
+		if strings.Contains(str, " ") {
+			return errors.New("should not contain space")
+		}
+		return nil
+	}
+
+	nameValidate := func(str string) error {
+		if err := validate(str); err != nil {
+			return err
+		}
+		str = strings.TrimSpace(str)
+		if strings.TrimSpace(info.baseDir) == "" {
+			if err := validateFileName(str); err != nil {
+				return err
+			}
+		}
+		// The project is extracted into a directory of the name
+		// unless another one is given.
+		if info.output == "" && info.baseDir == "" {
+			return checkDir(str, opts.existing)
+		}
+		return nil
+	}
+
+	getOpts := func(st initializr.SelectField) []huh.Option[string] {
+		var opts []huh.Option[string]
+		for _, lv := range st.Values {
+			opt := huh.NewOption(lv.Name, lv.Id)
+			if lv.Id == st.Default {
+				opt = opt.Selected(true)
+			}
+			opts = append(opts, opt)
+		}
+		return opts
+	}
+
+	getProjectOpts := func(pt initializr.ProjectTypeField) []huh.Option[string] {
+		var opts []huh.Option[string]
+		for _, lv := range pt.Values {
+			// Only the archives which can be extracted are supported,
+			// or else the build file alone.
+			isZip := lv.Action == "" || strings.HasSuffix(lv.Action, ".zip")
+			var opt huh.Option[string]
+			switch {
+			case lv.Tags["format"] == "project" && isZip:
+				opt = huh.NewOption(lv.Name, lv.Id)
+			case lv.Tags["format"] == "build":
+				opt = huh.NewOption(buildTypeName(lv), lv.Id)
+			default:
+				continue
+			}
+			if lv.Id == pt.Default {
+				opt = opt.Selected(true)
+			}
+			opts = append(opts, opt)
+		}
+		return opts
+	}
+
+	// hasFacet checks if any of the facets is in the wanted ones.
+	hasFacet := func(facets, wanted []string) bool {
+		for _, f := range facets {
+			for _, w := range wanted {
+				if f == w {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	getDepsOpts := func(mt initializr.DependencyField, bootVersion string, facets []string) []depOption {
+		var opts []depOption
+		for _, values := range mt.Values {
+			for _, dep := range values.Values {
+				if !dep.VersionRange.Contains(bootVersion) {
+					continue
+				}
+				// The selected dependencies are kept so that the
+				// selection is not lost by filtering.
+				selected := false
+				for _, id := range info.dependencies {
+					selected = selected || id == dep.Id
+				}
+				if len(facets) == 0 || selected || hasFacet(dep.Facets, facets) {
+					opt := depOption{id: dep.Id, label: dep.Name, group: values.Name, description: dep.Description}
+					if vr := dep.VersionRange.String(); vr != "" {
+						opt.note = "(Spring Boot " + vr + ")"
+					}
+					opts = append(opts, opt)
+				}
+			}
+		}
+		return opts
+	}
+
+	depsHeight := 22 // show 20 dependencies at once
+	if opts.plain {
+		depsHeight = 12
+	}
+	picker := newDepPicker(&info.dependencies, depsHeight)
+	picker.title = "Add dependencies"
+	picker.coordinates = data.coordinates
+
+	picker.build = func(bootVersion string, facets []string) []depOption {
+		depsOpts := getDepsOpts(data.Dependencies, bootVersion, facets)
+		if len(depsOpts) == 0 {
+			depsOpts = getDepsOpts(data.Dependencies, bootVersion, nil)
+		}
+		// The catalog projects are always shown as they do not
+		// depend on the boot version or the facets.
+		for _, p := range opts.catalog {
+			depsOpts = append(depsOpts, depOption{
+				id:    catalogPrefix + p.Name,
+				label: p.Name,
+				note:  p.Description,
+				group: "Spring CLI catalog",
+			})
+		}
+		picker.description = ""
+		if len(facets) > 0 {
+			picker.description = "Filtered by " + strings.Join(facets, ", ")
+		}
+		return depsOpts
+	}
+	// The group of the dependencies may be shown first when the
+	// answers are edited.
+	bootVersion := data.BootVersion.Default
+	if info.bootVersion != "" {
+		bootVersion = info.bootVersion
+	}
+	picker.refilter(bootVersion)
+	picker.prepare = func() {
+		bootVersion := data.BootVersion.Default
+		if info.bootVersion != "" {
+			bootVersion = info.bootVersion
+		}
+		// huh adds the choices of a multi-select twice in
+		// accessible mode.
+		picker.facets = unique(picker.facets)
+		picker.bundles = unique(picker.bundles)
+		picker.refilter(bootVersion)
+		if missing := picker.applyBundles(opts.bundles); len(missing) > 0 {
+			fmt.Fprintln(promptOutput(), "Skipped the unavailable dependencies of the bundle: "+strings.Join(missing, ", "))
+		}
+	}
+
+	infoFields := []huh.Field{
+		huh.NewInput().
+			Title("Name of the project").
+			Value(&info.name).
+			Placeholder(data.Name.Default).
+			Validate(nameValidate),
+
+		huh.NewInput().
+			Title("Group Id").
+			Value(&info.group).
+			Placeholder(data.GroupId.Default).
+			Validate(func(str string) error {
+				return validateGroupId(strings.TrimSpace(str))
+			}),
+
+		huh.NewInput().
+			Title("Artifact Id").
+			Value(&info.artifact).
+			Placeholder(data.ArtifactId.Default).
+			Validate(func(str string) error {
+				return validateArtifactId(strings.TrimSpace(str))
+			}),
+	}
+
+	// Older servers do not support the project version.
+	if data.Supports("version") {
+		infoFields = append(infoFields, huh.NewInput().
+			Title("Version").
+			Value(&info.version).
+			Placeholder(data.Version.Default).
+			Validate(validate))
+	}
+
+	description := newCountedText(&info.description, descriptionLimit)
+	description.Title("Write a short description").
+		Lines(3).
+		Placeholder(data.Description.Default)
+	infoFields = append(infoFields, description,
+		huh.NewInput().
+			Title("Package name").
+			Description("Leave empty to derive it from the group and the artifact id").
+			Value(&info.packageName).
+			Validate(func(str string) error {
+				return validatePackageName(strings.TrimSpace(str), info.language)
+			}),
+
+		huh.NewInput().
+			Title("Base directory").
+			Description("Leave empty to use the name of the project").
+			Value(&info.baseDir).
+			Validate(func(str string) error {
+				if err := validate(str); err != nil {
+					return err
+				}
+				if str = strings.TrimSpace(str); str != "" && info.output == "" {
+					return checkDir(str, opts.existing)
+				}
+				return nil
+			}),
+	)
+
+	infoFields = append(infoFields, huh.NewInput().
+		Title("Output directory").
+		Description("Leave empty to create the project in the current directory").
+		Value(&info.output).
+		Validate(func(str string) error {
+			if strings.TrimSpace(str) == "" {
+				return nil
+			}
+			return checkDir(info.dir(), opts.existing)
+		}))
+
+	groups := []*huh.Group{
+		huh.NewGroup(infoFields...),
+
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Pick a language").
+				Options(getOpts(data.Language)...).
+				Value(&info.language).
+				Validate(func(language string) error {
+					return validatePackageName(strings.TrimSpace(info.packageName), language)
+				}),
+
+			newJumpSelect(huh.NewSelect[string]().
+				Title("Java version"),
+				&info.javaVersion, getOpts(data.JavaVersion), data.JavaVersion.Default),
+
+			newVersionSelect(huh.NewSelect[string]().
+				Title("Spring Boot version"),
+				&info.bootVersion, getOpts(data.BootVersion), data.BootVersion.Default),
+
+			huh.NewSelect[string]().
+				Title("Type of the project").
+				Options(getProjectOpts(data.ProjectType)...).
+				Value(&info.projectType),
+
+			huh.NewSelect[string]().
+				Title("Packaging type").
+				Options(getOpts(data.Packaging)...).
+				Value(&info.packaging),
+		),
+	}
+
+	// Let the user narrow the dependencies by their facets if the
+	// server declares them.
+	var depFields []huh.Field
+	if all := data.Dependencies.Facets(); len(all) > 0 {
+		var facetOpts []huh.Option[string]
+		for _, f := range all {
+			facetOpts = append(facetOpts, huh.NewOption(f, f))
+		}
+		depFields = append(depFields, huh.NewMultiSelect[string]().
+			Title("Filter dependencies by capability").
+			Description("Select none to show all the dependencies").
+			Options(facetOpts...).
+			Value(&picker.facets))
+	}
+	if len(opts.bundles) > 0 {
+		var bundleOpts []huh.Option[string]
+		for _, name := range bundleNames(opts.bundles) {
+			label := name + " (" + strings.Join(opts.bundles[name], ", ") + ")"
+			bundleOpts = append(bundleOpts, huh.NewOption(label, name))
+		}
+		depFields = append(depFields, huh.NewMultiSelect[string]().
+			Title("Dependency bundles").
+			Description("Select the dependencies of a bundle at once").
+			Options(bundleOpts...).
+			Value(&picker.bundles))
+	}
+	groups = append(groups, huh.NewGroup(append(depFields, picker)...))
+	var addOnOpts []huh.Option[string]
+	for _, a := range addOns {
+		addOnOpts = append(addOnOpts, huh.NewOption(a.name, a.id))
+	}
+	groups = append(groups, huh.NewGroup(
+		huh.NewMultiSelect[string]().
+			Title("Add-ons").
+			Description("Files which are added to the project after it is generated").
+			Options(addOnOpts...).
+			Value(&info.addOns),
+	))
+	if len(data.Extra) > 0 {
+		groups = append(groups, newExtraGroup(info, data.Extra))
+	}
+
+	form := huh.NewForm(groups...)
+	if opts.plain {
+		return form.WithTheme(plainTheme()).WithShowHelp(false), picker
+	}
+	return form.WithTheme(huh.ThemeDracula()), picker
+}
+
+// newExtraGroup returns the group of the additional fields
+// declared by the server.
+func newExtraGroup(info *projectInfo, fields []extraField) *huh.Group {
+	if info.extra == nil {
+		info.extra = make(map[string]*string)
+	}
+
+	var huhFields []huh.Field
+	for _, field := range fields {
+		val, ok := info.extra[field.Id]
+		if !ok {
+			val = new(string)
+			info.extra[field.Id] = val
+		}
+
+		if field.Type == "text" {
+			huhFields = append(huhFields, huh.NewInput().
+				Title(field.Id).
+				Value(val).
+				Placeholder(field.Default))
+			continue
+		}
+
+		var opts []huh.Option[string]
+		for _, v := range field.Values {
+			opt := huh.NewOption(v.Name, v.Id)
+			if v.Id == field.Default {
+				opt = opt.Selected(true)
+			}
+			opts = append(opts, opt)
+		}
+		huhFields = append(huhFields, huh.NewSelect[string]().
+			Title(field.Id).
+			Options(opts...).
+			Value(val))
+	}
+	return huh.NewGroup(huhFields...).Title("Additional options")
+}
+
+// plainTheme returns a colorless theme which puts the fields
+// close to each other.
+func plainTheme() *huh.Theme {
+	theme := huh.ThemeBase()
+	theme.FieldSeparator = lipgloss.NewStyle().SetString("\n")
+	return theme
+}
+
+func newSpinner() spinner.Model {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#bd93f9"))
+	return spinner.New(
+		spinner.WithSpinner(spinner.Dot),
+		spinner.WithStyle(style),
+	)
+}
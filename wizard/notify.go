@@ -0,0 +1,40 @@
+package wizard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// notifyAfter is the duration after which the completion of a task
+// is notified as the user has likely switched to another window.
+const notifyAfter = 5 * time.Second
+
+// notify rings the terminal bell and sends a desktop notification
+// with the given message. Errors are ignored since a missing
+// notifier should not affect the generation.
+func notify(title, msg string) {
+	fmt.Fprint(os.Stderr, "\a")
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", msg, title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		// The texts are passed in the environment so that they are
+		// not parsed as a part of the script.
+		script := `Add-Type -AssemblyName System.Windows.Forms;` +
+			`$n = New-Object System.Windows.Forms.NotifyIcon;` +
+			`$n.Icon = [System.Drawing.SystemIcons]::Information;` +
+			`$n.Visible = $true;` +
+			`$n.ShowBalloonTip(5000, $env:STARTSPRING_TITLE, $env:STARTSPRING_MESSAGE, 'Info')`
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+		cmd.Env = append(os.Environ(), "STARTSPRING_TITLE="+title, "STARTSPRING_MESSAGE="+msg)
+	default:
+		cmd = exec.Command("notify-send", title, msg)
+	}
+	_ = cmd.Run()
+}
@@ -0,0 +1,40 @@
+package wizard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// offlineTransport answers the requests with the mock server in
+// the same process, so that a barebones project can be generated
+// from the bundled templates when no server is reachable.
+type offlineTransport struct {
+	handler http.Handler
+	// server is the url which the requests are sent to.
+	server string
+}
+
+func (t offlineTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// The mock is at the root while a self-hosted server may be at
+	// a path.
+	if u, err := url.Parse(t.server); err == nil && u.Path != "" {
+		r = r.Clone(r.Context())
+		r.URL.Path = strings.TrimPrefix(r.URL.Path, u.Path)
+	}
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, r)
+	return rec.Result(), nil
+}
+
+// newOfflineClient returns a client of the server which never leaves
+// the process.
+func newOfflineClient(server string) *initializr.Client {
+	return &initializr.Client{
+		HTTPClient: &http.Client{Transport: offlineTransport{&mockServer{}, server}},
+		URL:        server,
+	}
+}
@@ -0,0 +1,396 @@
+package wizard
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// buildAddition is what the build file of a project needs for new
+// dependencies: their coordinates, the BOMs which manage their
+// versions and the repositories which have them.
+type buildAddition struct {
+	deps  []addedDependency
+	boms  []addedDependency
+	repos []addedRepository
+}
+
+type addedDependency struct {
+	groupId    string
+	artifactId string
+	version    string
+	// scope is the scope of the Initializr, e.g. runtime or
+	// annotationProcessor.
+	scope string
+}
+
+func (d addedDependency) coords() string {
+	s := d.groupId + ":" + d.artifactId
+	if d.version != "" {
+		s += ":" + d.version
+	}
+	return s
+}
+
+type addedRepository struct {
+	id   string
+	name string
+	url  string
+}
+
+// additionOf returns what the build file needs for the dependencies.
+func additionOf(d *initializr.Dependencies, ids []string) buildAddition {
+	var a buildAddition
+	for _, id := range ids {
+		if dep, ok := d.Dependencies[id]; ok {
+			a.deps = append(a.deps, addedDependency{dep.GroupId, dep.ArtifactId, dep.Version, dep.Scope})
+		}
+	}
+	boms, repos := d.Extras(ids)
+	for _, b := range boms {
+		a.boms = append(a.boms, addedDependency{b.GroupId, b.ArtifactId, b.Version, "import"})
+	}
+	for _, r := range repos {
+		a.repos = append(a.repos, addedRepository{r.Id, r.Name, r.Url})
+	}
+	return a
+}
+
+// without leaves out the BOMs and the repositories which the build
+// file already declares.
+func (a buildAddition) without(content string) buildAddition {
+	var boms []addedDependency
+	for _, b := range a.boms {
+		if !strings.Contains(content, b.artifactId) {
+			boms = append(boms, b)
+		}
+	}
+	var repos []addedRepository
+	for _, r := range a.repos {
+		if !strings.Contains(content, strings.TrimSuffix(r.url, "/")) {
+			repos = append(repos, r)
+		}
+	}
+	a.boms, a.repos = boms, repos
+	return a
+}
+
+// patchBuildFile adds the dependencies to the content of the build
+// file at the path.
+func patchBuildFile(path, content string, a buildAddition) (string, error) {
+	a = a.without(content)
+	switch name := filepath.Base(path); name {
+	case "pom.xml":
+		return patchPom(content, a)
+	case "build.gradle":
+		return patchGradle(content, a, false), nil
+	case "build.gradle.kts":
+		return patchGradle(content, a, true), nil
+	default:
+		return "", fmt.Errorf("unsupported build file %s", name)
+	}
+}
+
+// insertion is a text inserted at an offset of the build file.
+type insertion struct {
+	at   int
+	text string
+}
+
+// insert inserts the texts. Texts at the same offset keep their order.
+func insert(content string, ins []insertion) string {
+	sort.SliceStable(ins, func(i, j int) bool { return ins[i].at < ins[j].at })
+	var sb strings.Builder
+	last := 0
+	for _, in := range ins {
+		sb.WriteString(content[last:in.at])
+		sb.WriteString(in.text)
+		last = in.at
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
+}
+
+// lineStart returns the offset of the start of the line of the
+// offset and the indentation of the line up to it.
+func lineStart(content string, at int) (int, string) {
+	start := strings.LastIndex(content[:at], "\n") + 1
+	indent := content[start:at]
+	if strings.TrimSpace(indent) != "" {
+		// The closing tag or brace is not on a line of its own.
+		return at, ""
+	}
+	return start, indent
+}
+
+// indentLines indents the lines and ends each with a newline.
+func indentLines(indent string, lines []string) string {
+	var sb strings.Builder
+	for _, l := range lines {
+		sb.WriteString(indent + l + "\n")
+	}
+	return sb.String()
+}
+
+// pomTag returns the element with the text.
+func pomTag(name, text string) string {
+	return fmt.Sprintf("<%s>%s</%s>", name, text, name)
+}
+
+// pomElement returns the lines of an element with simple children,
+// leaving out the empty ones.
+func pomElement(unit, name string, children [][2]string) []string {
+	lines := []string{"<" + name + ">"}
+	for _, c := range children {
+		if c[1] != "" {
+			lines = append(lines, unit+pomTag(c[0], c[1]))
+		}
+	}
+	return append(lines, "</"+name+">")
+}
+
+func pomDependency(unit string, d addedDependency) []string {
+	var scope, optional string
+	switch d.scope {
+	case "runtime", "test", "provided", "import":
+		scope = d.scope
+	case "annotationProcessor":
+		optional = "true"
+	}
+	typ := ""
+	if d.scope == "import" {
+		typ = "pom"
+	}
+	return pomElement(unit, "dependency", [][2]string{
+		{"groupId", d.groupId},
+		{"artifactId", d.artifactId},
+		{"version", d.version},
+		{"type", typ},
+		{"scope", scope},
+		{"optional", optional},
+	})
+}
+
+// wrap returns the lines of the element around the lines.
+func wrap(unit, name string, lines []string) []string {
+	wrapped := []string{"<" + name + ">"}
+	for _, l := range lines {
+		wrapped = append(wrapped, unit+l)
+	}
+	return append(wrapped, "</"+name+">")
+}
+
+// patchPom adds the dependencies to the pom. They are inserted
+// before the closing tags of their sections, which are created if
+// the pom does not have them yet.
+func patchPom(content string, a buildAddition) (string, error) {
+	// ends holds the offsets of the closing tags by their path.
+	ends := make(map[string]int)
+	unit := ""
+	var stack []string
+	dec := xml.NewDecoder(strings.NewReader(content))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return "", fmt.Errorf("invalid pom.xml: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+		case xml.EndElement:
+			elem := strings.Join(stack, "/")
+			at := strings.LastIndex(content[:dec.InputOffset()], "</")
+			if _, ok := ends[elem]; !ok {
+				ends[elem] = at
+			}
+			// The children of the project are indented by one unit.
+			if len(stack) == 2 && unit == "" {
+				_, unit = lineStart(content, at)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if _, ok := ends["project"]; !ok {
+		return "", errors.New("invalid pom.xml: no project element")
+	}
+	if unit == "" {
+		unit = "\t"
+	}
+
+	var ins []insertion
+	// add inserts the lines into the element at the path, or into
+	// its parent wrapped in the element if it is missing.
+	var add func(elem string, depth int, lines []string)
+	add = func(elem string, depth int, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		if at, ok := ends[elem]; ok {
+			start, _ := lineStart(content, at)
+			ins = append(ins, insertion{start, indentLines(strings.Repeat(unit, depth), lines)})
+			return
+		}
+		i := strings.LastIndex(elem, "/")
+		add(elem[:i], depth-1, wrap(unit, elem[i+1:], lines))
+	}
+
+	var deps, boms, repos []string
+	for _, d := range a.deps {
+		deps = append(deps, pomDependency(unit, d)...)
+	}
+	for _, b := range a.boms {
+		boms = append(boms, pomDependency(unit, b)...)
+	}
+	for _, r := range a.repos {
+		repos = append(repos, pomElement(unit, "repository", [][2]string{
+			{"id", r.id}, {"name", r.name}, {"url", r.url},
+		})...)
+	}
+	add("project/dependencies", 2, deps)
+	add("project/dependencyManagement/dependencies", 3, boms)
+	add("project/repositories", 2, repos)
+	return insert(content, ins), nil
+}
+
+// gradleBlock matches the start of a top level block of the build
+// script.
+func gradleBlock(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(name) + `\s*\{`)
+}
+
+// blockEnd returns the offset of the brace which closes the block
+// whose opening brace ends at the offset, or -1 if it is not closed.
+func blockEnd(content string, open int) int {
+	depth := 1
+	for i := open; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// gradleIndent returns the indentation of the first indented line of
+// the script, or a tab.
+func gradleIndent(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != line && trimmed != "" {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "\t"
+}
+
+// gradleConfigurations returns the configurations of the dependency
+// as the Initializr declares them.
+func gradleConfigurations(d addedDependency) []string {
+	switch {
+	case d.artifactId == "spring-boot-devtools" || d.artifactId == "spring-boot-docker-compose":
+		return []string{"developmentOnly"}
+	case d.scope == "annotationProcessor":
+		return []string{"compileOnly", "annotationProcessor"}
+	case d.scope == "runtime":
+		return []string{"runtimeOnly"}
+	case d.scope == "test":
+		return []string{"testImplementation"}
+	case d.scope == "provided":
+		return []string{"providedRuntime"}
+	}
+	return []string{"implementation"}
+}
+
+// patchGradle adds the dependencies to the groovy or the kotlin
+// build script. The BOMs are imported with the dependency management
+// plugin if it is applied, otherwise as platforms.
+func patchGradle(content string, a buildAddition, kotlin bool) string {
+	unit := gradleIndent(content)
+	quote := func(s string) string {
+		if kotlin {
+			return `("` + s + `")`
+		}
+		return ` '` + s + `'`
+	}
+
+	var deps, boms, repos []string
+	for _, d := range a.deps {
+		for _, config := range gradleConfigurations(d) {
+			deps = append(deps, config+quote(d.coords()))
+		}
+	}
+	managed := strings.Contains(content, "io.spring.dependency-management")
+	for _, b := range a.boms {
+		switch {
+		case !managed && kotlin:
+			deps = append(deps, `implementation(platform("`+b.coords()+`"))`)
+		case !managed:
+			deps = append(deps, `implementation platform('`+b.coords()+`')`)
+		default:
+			boms = append(boms, "mavenBom"+strings.Replace(quote(b.coords()), "'", `"`, 2))
+		}
+	}
+	for _, r := range a.repos {
+		if kotlin {
+			repos = append(repos, `maven { url = uri("`+r.url+`") }`)
+		} else {
+			repos = append(repos, `maven { url '`+r.url+`' }`)
+		}
+	}
+
+	var ins []insertion
+	// appended are the blocks which the script does not have yet.
+	var appended []string
+	// add inserts the lines at the end of the block which starts at
+	// the match of the pattern, and reports whether it is found.
+	add := func(block *regexp.Regexp, indent string, lines []string) bool {
+		loc := block.FindStringIndex(content)
+		if loc == nil {
+			return false
+		}
+		end := blockEnd(content, loc[1])
+		if end < 0 {
+			return false
+		}
+		start, _ := lineStart(content, end)
+		ins = append(ins, insertion{start, indentLines(indent, lines)})
+		return true
+	}
+	for _, b := range []struct {
+		name  string
+		lines []string
+	}{{"repositories", repos}, {"dependencies", deps}} {
+		if len(b.lines) > 0 && !add(gradleBlock(b.name), unit, b.lines) {
+			appended = append(appended, b.name+" {\n"+indentLines(unit, b.lines)+"}\n")
+		}
+	}
+	// The imports are expected in the top level block of the plugin,
+	// which is the only one the Initializr writes.
+	imports := regexp.MustCompile(`(?m)^dependencyManagement\s*\{\s*imports\s*\{`)
+	if len(boms) > 0 && !add(imports, unit+unit, boms) {
+		appended = append(appended, "dependencyManagement {\n"+unit+"imports {\n"+
+			indentLines(unit+unit, boms)+unit+"}\n}\n")
+	}
+
+	patched := insert(content, ins)
+	if len(appended) > 0 && !strings.HasSuffix(patched, "\n") {
+		patched += "\n"
+	}
+	for _, block := range appended {
+		patched += "\n" + block
+	}
+	return patched
+}
@@ -0,0 +1,102 @@
+package wizard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var warnStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f1fa8c"))
+
+// preflightMsg is sent with the warnings about the local tools
+// which are missing to build and run the project.
+type preflightMsg struct{ warnings []string }
+
+// preflightCmd checks the local tools while the project is
+// reviewed. It works on a copy of the project as the generation may
+// fill in the defaults meanwhile.
+func preflightCmd(data *metadata, info *projectInfo, push bool) tea.Cmd {
+	return func() tea.Msg {
+		applyDefaults(info, data)
+		return preflightMsg{preflight(info, push)}
+	}
+}
+
+// preflight checks that the tools which the project needs exist
+// and returns the warnings about the missing ones. git is needed if
+// the project is pushed.
+func preflight(info *projectInfo, push bool) []string {
+	var warnings []string
+
+	out, err := javacVersion()
+	if err != nil {
+		warnings = append(warnings, "No JDK found, install a JDK and set JAVA_HOME to build the project")
+	} else if v := javaMajor(out); v > 0 {
+		if want, err := strconv.Atoi(info.javaVersion); err == nil && v < want {
+			warnings = append(warnings, fmt.Sprintf(
+				"The JDK is version %d but the project needs Java %d", v, want))
+		}
+	}
+
+	if push {
+		if _, err := exec.LookPath("git"); err != nil {
+			warnings = append(warnings, "No git found, it is needed to push the project")
+		}
+	}
+
+	if need := dockerNeed(info); need != "" {
+		if _, err := exec.LookPath("docker"); err != nil {
+			warnings = append(warnings, "No docker found, it is needed by "+need)
+		}
+	}
+	return warnings
+}
+
+// dockerNeed returns what needs docker in the project, the first
+// dependency or add-on which runs containers, or "" if nothing does.
+func dockerNeed(info *projectInfo) string {
+	for _, dep := range info.dependencies {
+		if dep == "docker-compose" || dep == "testcontainers" {
+			return "the " + dep + " dependency"
+		}
+	}
+	for _, id := range info.addOns {
+		if id == "docker" || id == "devcontainer" {
+			return "the " + id + " add-on"
+		}
+	}
+	return ""
+}
+
+// javacVersion returns the output of javac -version of JAVA_HOME
+// or the PATH.
+func javacVersion() (string, error) {
+	javac := "javac"
+	if home := os.Getenv("JAVA_HOME"); home != "" {
+		javac = filepath.Join(home, "bin", "javac")
+	}
+	out, err := exec.Command(javac, "-version").CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// javaMajor returns the major version of the javac -version output,
+// e.g. 17 for javac 17.0.2 and 8 for javac 1.8.0_292. It returns 0
+// if the output is unknown.
+func javaMajor(out string) int {
+	fields := strings.Fields(out)
+	if len(fields) < 2 {
+		return 0
+	}
+	v := strings.TrimPrefix(fields[1], "1.")
+	if i := strings.IndexAny(v, ".-_+"); i >= 0 {
+		v = v[:i]
+	}
+	major, _ := strconv.Atoi(v)
+	return major
+}
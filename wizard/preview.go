@@ -0,0 +1,60 @@
+package wizard
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// previewMsg is sent with the build file of the project with the
+// current selections.
+type previewMsg struct {
+	name    string
+	content string
+}
+
+// previewCmd fetches the build file of the project with the current
+// selections. It takes a copy of the project as the form keeps
+// editing it meanwhile.
+func previewCmd(client *initializr.Client, data *metadata, info *projectInfo) tea.Cmd {
+	return func() tea.Msg {
+		applyDefaults(info, data)
+		action, err := data.Action(data.BuildType(info.projectType), "build")
+		if err != nil {
+			return noticeMsg(err.Error())
+		}
+		resp, err := getProjectFile(context.Background(), client, action, info)
+		if err != nil {
+			return noticeMsg(err.Error())
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return noticeMsg(fmt.Sprintf("failed to preview the build file: %s", resp.Status))
+		}
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return noticeMsg(err.Error())
+		}
+		return previewMsg{name: action[1:], content: string(b)}
+	}
+}
+
+// newPreview returns the pager of the build file which fits in the
+// window leaving space for the title and the help line.
+func newPreview(content string, width, height int) viewport.Model {
+	if width <= 0 {
+		width, height = 80, 24
+	}
+	h := height - 4
+	if h < 1 {
+		h = 1
+	}
+	vp := viewport.New(width, h)
+	vp.SetContent(content)
+	return vp
+}
@@ -0,0 +1,171 @@
+package wizard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"gopkg.in/yaml.v3"
+)
+
+// profilesFile returns the file of the saved profiles, e.g.
+// ~/.config/startspring/profiles.yaml. A profile holds the answers
+// shared by the projects of a team, keyed by its name:
+//
+//	myteam:
+//	  groupId: com.acme
+//	  javaVersion: "21"
+//	  dependencies: [web, actuator]
+func profilesFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "startspring", "profiles.yaml"), nil
+}
+
+// readProfiles reads the saved profiles. There are none if the file
+// does not exist.
+func readProfiles() (map[string]config, error) {
+	path, err := profilesFile()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var profiles map[string]config
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&profiles); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid profiles %s: %w", path, err)
+	}
+	return profiles, nil
+}
+
+// profileNames returns the sorted names of the profiles.
+func profileNames(profiles map[string]config) []string {
+	var names []string
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadProfile returns the project of the saved profile.
+func loadProfile(name string) (*projectInfo, error) {
+	profiles, err := readProfiles()
+	if err != nil {
+		return nil, err
+	}
+	cfg, ok := profiles[name]
+	if !ok {
+		if len(profiles) == 0 {
+			return nil, fmt.Errorf("unknown profile '%s', no profile is saved yet", name)
+		}
+		return nil, fmt.Errorf("unknown profile '%s', saved profiles: %s",
+			name, strings.Join(profileNames(profiles), ", "))
+	}
+	return cfg.projectInfo(), nil
+}
+
+// saveProfile saves the answers of the project as the profile. The
+// answers which only belong to this project, like its name, are
+// left out.
+func saveProfile(name string, info *projectInfo) error {
+	profiles, err := readProfiles()
+	if err != nil {
+		return err
+	}
+	if profiles == nil {
+		profiles = make(map[string]config)
+	}
+	cfg := newConfig(info)
+	cfg.Name, cfg.ArtifactId, cfg.Description = "", "", ""
+	cfg.PackageName, cfg.BaseDir = "", ""
+	profiles[name] = cfg
+
+	b, err := yaml.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	path, err := profilesFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// fill fills the empty answers of the project with the ones of the
+// profile.
+func (info *projectInfo) fill(p *projectInfo) {
+	fields := []struct{ val, def *string }{
+		{&info.name, &p.name},
+		{&info.group, &p.group},
+		{&info.artifact, &p.artifact},
+		{&info.version, &p.version},
+		{&info.description, &p.description},
+		{&info.packageName, &p.packageName},
+		{&info.baseDir, &p.baseDir},
+		{&info.projectType, &p.projectType},
+		{&info.language, &p.language},
+		{&info.bootVersion, &p.bootVersion},
+		{&info.packaging, &p.packaging},
+		{&info.javaVersion, &p.javaVersion},
+	}
+	for _, f := range fields {
+		if *f.val == "" {
+			*f.val = *f.def
+		}
+	}
+	if len(info.dependencies) == 0 {
+		info.dependencies = append([]string(nil), p.dependencies...)
+	}
+	if len(info.addOns) == 0 {
+		info.addOns = append([]string(nil), p.addOns...)
+	}
+	for k, v := range p.extra {
+		if cur, ok := info.extra[k]; ok && *cur != "" {
+			continue
+		}
+		if info.extra == nil {
+			info.extra = make(map[string]*string)
+		}
+		v := *v
+		info.extra[k] = &v
+	}
+}
+
+// newPresetForm returns the form which asks for the saved profile
+// to start with, or none.
+func newPresetForm(names []string, preset *string, opts options) *huh.Form {
+	options := []huh.Option[string]{huh.NewOption("None", "")}
+	for _, name := range names {
+		options = append(options, huh.NewOption(name, name))
+	}
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title("Load preset").
+			Description("Fill in the answers with a saved profile").
+			Options(options...).
+			Value(preset),
+	))
+	if opts.plain {
+		return form.WithTheme(plainTheme()).WithShowHelp(false)
+	}
+	return form.WithTheme(huh.ThemeDracula())
+}
@@ -0,0 +1,119 @@
+package wizard
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxBarWidth is the width of the progress bar in a wide terminal.
+const maxBarWidth = 60
+
+// newProgressBar returns the bar of the download, which shows the
+// percentage in the text next to it.
+func newProgressBar() progress.Model {
+	bar := progress.New(progress.WithSolidFill("#bd93f9"), progress.WithoutPercentage())
+	bar.EmptyColor = "#44475a"
+	return bar
+}
+
+// progressMsg reports how much of the project archive is downloaded.
+// The total is 0 if the server does not send the length.
+type progressMsg struct{ read, total int64 }
+
+// progressReader reports the bytes read from the body of the
+// response.
+type progressReader struct {
+	r      io.Reader
+	read   int64
+	total  int64
+	report func(progressMsg)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.read += int64(n)
+		p.report(progressMsg{p.read, p.total})
+	}
+	return n, err
+}
+
+// reportTo returns a report function which sends the progress to the
+// channel without blocking the download. An update which the UI has
+// not received yet is replaced by the newer one.
+func reportTo(ch chan progressMsg) func(progressMsg) {
+	return func(msg progressMsg) {
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// waitProgress waits for the next progress of the download. It stops
+// once the channel is closed.
+func waitProgress(ch chan progressMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// progressView shows the progress of the download, measured by the
+// length sent by the server or else by the estimated size.
+func (m model) progressView() string {
+	total, about := m.downloadSize, ""
+	if total == 0 && m.estimatedSize > 0 {
+		total, about = m.estimatedSize, "about "
+	}
+
+	var text string
+	switch {
+	case m.canceling:
+		text = "Canceling..."
+	case m.downloaded == 0 && m.estimatedSize > 0:
+		text = fmt.Sprintf("Generating project (about %s)...", formatSize(m.estimatedSize))
+	case m.downloaded == 0:
+		text = "Generating project..."
+	case m.downloadSize > 0 && m.downloaded >= m.downloadSize:
+		text = "Extracting project..."
+	case total > 0:
+		ratio := float64(m.downloaded) / float64(total)
+		// The estimate may be too small, so it is never done.
+		if about != "" && ratio > 0.99 {
+			ratio = 0.99
+		}
+		text = fmt.Sprintf("Downloading project %3.f%% · %s of %s%s",
+			ratio*100, formatSize(m.downloaded), about, formatSize(total))
+		if m.opts.plain {
+			return text
+		}
+		// The bar takes the width which the text leaves.
+		bar := m.bar
+		bar.Width = maxBarWidth
+		if w := m.width - lipgloss.Width(text) - 1; m.width > 0 && w < maxBarWidth {
+			bar.Width = w
+		}
+		if bar.Width < 10 {
+			return text
+		}
+		return bar.ViewAs(ratio) + " " + text
+	default:
+		text = fmt.Sprintf("Downloading project · %s", formatSize(m.downloaded))
+	}
+	if m.opts.plain {
+		return text
+	}
+	return fmt.Sprintf("%s %s", m.spinner.View(), text)
+}
@@ -0,0 +1,364 @@
+package wizard
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// projectInfo bundles all the information of
+// a spring project.
+type projectInfo struct {
+	name         string
+	group        string
+	artifact     string
+	version      string
+	description  string
+	packageName  string
+	baseDir      string
+	projectType  string
+	language     string
+	bootVersion  string
+	packaging    string
+	javaVersion  string
+	dependencies []string
+	// addOns are the ids of the add-ons which add files to the
+	// project after it is extracted.
+	addOns []string
+
+	// extra holds the values of the additional fields declared
+	// by the server, keyed by the id of the field.
+	extra map[string]*string
+
+	// output is the directory where the project is extracted
+	// instead of the base directory. It is not sent to the server.
+	output string
+}
+
+// dir returns the directory where the project is extracted.
+func (info *projectInfo) dir() string {
+	if info.output == "" {
+		return info.resolvedBaseDir()
+	}
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(info.output, "~/") {
+		return filepath.Join(home, info.output[2:])
+	}
+	return info.output
+}
+
+// clone returns a deep copy of the project.
+func (info *projectInfo) clone() *projectInfo {
+	c := *info
+	c.dependencies = append([]string(nil), info.dependencies...)
+	c.addOns = append([]string(nil), info.addOns...)
+	if info.extra != nil {
+		c.extra = make(map[string]*string, len(info.extra))
+		for k, v := range info.extra {
+			v := *v
+			c.extra[k] = &v
+		}
+	}
+	return &c
+}
+
+// resolvedBaseDir returns the base directory of the project, which
+// is the name of the project if it is not given.
+func (info *projectInfo) resolvedBaseDir() string {
+	if dir := strings.TrimSpace(info.baseDir); dir != "" {
+		return dir
+	}
+	return strings.TrimSpace(info.name)
+}
+
+// resolvedPackageName returns the package name of the project. If it
+// is not given, it is derived from the group and the artifact id.
+func (info *projectInfo) resolvedPackageName() string {
+	if pkg := strings.TrimSpace(info.packageName); pkg != "" {
+		return pkg
+	}
+	if info.group == "" || info.artifact == "" {
+		return ""
+	}
+	return derivePackageName(info.group, info.artifact, info.language)
+}
+
+// applyDefaults trims the values of the project and fills the empty
+// ones with the defaults of the server.
+func applyDefaults(info *projectInfo, data *metadata) {
+	type defaultValue struct {
+		val *string
+		def string
+	}
+	defaults := []defaultValue{
+		{&info.name, data.Name.Default},
+		{&info.group, data.GroupId.Default},
+		{&info.artifact, data.ArtifactId.Default},
+		{&info.version, data.Version.Default},
+		{&info.description, data.Description.Default},
+		{&info.language, data.Language.Default},
+		{&info.javaVersion, data.JavaVersion.Default},
+		{&info.bootVersion, data.BootVersion.Default},
+		{&info.projectType, data.ProjectType.Default},
+		{&info.packaging, data.Packaging.Default},
+	}
+	for _, field := range data.Extra {
+		if val, ok := info.extra[field.Id]; ok {
+			defaults = append(defaults, defaultValue{val, field.Default})
+		}
+	}
+
+	for _, d := range defaults {
+		*d.val = strings.TrimSpace(*d.val)
+		if *d.val == "" {
+			*d.val = d.def
+		}
+	}
+}
+
+// options holds the command line options.
+type options struct {
+	web     bool
+	qr      bool
+	plain   bool
+	explore bool
+	// accessible asks the questions one per line instead of showing
+	// the styled UI, for screen readers and dumb terminals.
+	accessible bool
+	// share prints the share link and the curl command of the
+	// project instead of generating it.
+	share bool
+	// reproducible normalizes the extracted project.
+	reproducible bool
+	lint         bool
+	// offline generates the project from the bundled templates.
+	offline bool
+	// eol is the line ending of the extracted text files.
+	eol string
+	// existing is the policy for a project directory which exists
+	// and is not empty.
+	existing string
+	// catalog holds the projects of the Spring CLI catalogs which
+	// can be applied after generation.
+	catalog []catalogProject
+	// profiles are the saved profiles which can be loaded in the
+	// first step of the form.
+	profiles map[string]config
+	// jsonOut is the file where the summary of the generated project
+	// is written as json, "-" for stdout.
+	jsonOut string
+	// bundles are the named sets of dependencies which can be
+	// selected at once in the form.
+	bundles map[string][]string
+	// push is the git host where a repository is created for the
+	// project and the initial commit is pushed, github or gitlab.
+	push string
+	// repo is the name of the repository, optionally with its owner.
+	repo string
+	// public creates a public repository instead of a private one.
+	public bool
+	// open is the editor which opens the generated project, auto for
+	// the first detected one.
+	open string
+	// editors are the configured editor commands by name.
+	editors map[string]editorCommand
+	http    httpOptions
+}
+
+// defaultServerURL is the url of the public Spring Initializr server.
+const defaultServerURL = initializr.DefaultURL
+
+func getMetaData(client *initializr.Client) (*metadata, error) {
+	md, err := client.Metadata()
+	if err != nil {
+		return nil, err
+	}
+	return &metadata{Metadata: *md, Extra: extraFields(md.Unknown)}, nil
+}
+
+// fetchMetadata fetches the metadata and, at the same time, the
+// dependencies resolved for the default boot version. The launch does
+// not wait for the dependencies as they are only needed later, and a
+// server which does not resolve them still works.
+func fetchMetadata(client *initializr.Client) (*metadata, error) {
+	prefetch := &depsPrefetch{done: make(chan struct{})}
+	go func() {
+		defer close(prefetch.done)
+		if deps, err := getDependencies(client, ""); err == nil {
+			prefetch.deps = deps
+		}
+	}()
+
+	data, err := getMetaData(client)
+	if err != nil {
+		return nil, err
+	}
+	data.prefetch = prefetch
+	return data, nil
+}
+
+// extras returns the BOMs and the repositories which are added
+// to the build file for the given dependencies.
+func extras(d *initializr.Dependencies, ids []string) (boms, repos []string) {
+	b, r := d.Extras(ids)
+	for _, bom := range b {
+		boms = append(boms, fmt.Sprintf("%s:%s:%s", bom.GroupId, bom.ArtifactId, bom.Version))
+	}
+	for _, repo := range r {
+		repos = append(repos, fmt.Sprintf("%s (%s)", repo.Name, repo.Url))
+	}
+	return boms, repos
+}
+
+// getDependencies resolves the dependencies for the boot version, or
+// for the default one of the server if it is empty.
+func getDependencies(client *initializr.Client, bootVersion string) (*initializr.Dependencies, error) {
+	return client.Dependencies(bootVersion)
+}
+
+// request returns the request of the project to the server.
+func (info *projectInfo) request() initializr.GenerateRequest {
+	// The catalog projects are not known by the server.
+	deps, _ := splitCatalog(info.dependencies, nil)
+	r := initializr.GenerateRequest{
+		Name:        info.name,
+		GroupId:     info.group,
+		ArtifactId:  info.artifact,
+		Version:     info.version,
+		Description: info.description,
+		PackageName: info.resolvedPackageName(),
+		BaseDir:     info.resolvedBaseDir(),
+
+		Language:    info.language,
+		JavaVersion: info.javaVersion,
+		BootVersion: info.bootVersion,
+		Type:        info.projectType,
+		Packaging:   info.packaging,
+
+		Dependencies: deps,
+	}
+	for id, val := range info.extra {
+		if r.Params == nil {
+			r.Params = make(map[string]string)
+		}
+		r.Params[id] = *val
+	}
+	return r
+}
+
+// projectForm returns the form values to request the project
+// from the server.
+func projectForm(info *projectInfo) url.Values {
+	return info.request().Form()
+}
+
+// getProjectFile requests the project using the given action of
+// the server, e.g. /pom.xml to get only the build file.
+func getProjectFile(ctx context.Context, client *initializr.Client, action string, info *projectInfo) (*http.Response, error) {
+	return client.FetchContext(ctx, action, info.request())
+}
+
+// unzip extracts the project archive into the directory of the
+// project, which is handled as the existing option says if it is
+// not empty. An overwritten directory is only replaced once the
+// project is extracted. The entries are extracted in the order of
+// their names if it needs to be reproducible, and the line endings
+// of the text files are converted as the eol option says. finish, if
+// not nil, runs the later steps on the extracted project before an
+// overwritten directory is replaced. Nothing is left of the extraction
+// if it or finish fails or the context is canceled. It returns the
+// slash separated paths of the extracted files.
+func unzip(ctx context.Context, a *archive, info *projectInfo, opts options, finish func(dir string) error) (extracted []string, err error) {
+	zipReader, err := a.reader()
+	if err != nil {
+		return nil, err
+	}
+	files := zipReader.File
+	if opts.reproducible {
+		files = append([]*zip.File(nil), files...)
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].Name < files[j].Name
+		})
+	}
+
+	dir, err := filepath.Abs(info.dir())
+	if err != nil {
+		return nil, err
+	}
+	_, statErr := os.Stat(dir)
+	target, err := prepareDir(dir, opts.existing)
+	if err != nil {
+		return nil, err
+	}
+	x, err := newExtraction(target, target != dir || os.IsNotExist(statErr))
+	if err != nil {
+		return nil, err
+	}
+	// Nothing is left of a failed extraction, not even the files of
+	// the entries which were extracted before it failed.
+	defer func() {
+		if err != nil {
+			x.cleanUp()
+		}
+	}()
+
+	for _, zf := range files {
+		// A canceled extraction is removed like a failed one.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// The entries are put in the base directory by the server,
+		// but not every server supports it.
+		name := strings.TrimPrefix(zf.Name, filepath.ToSlash(info.resolvedBaseDir())+"/")
+		if name == "" {
+			continue
+		}
+		if runtime.GOOS == "windows" {
+			if err := checkWindowsName(name); err != nil {
+				return nil, err
+			}
+		}
+		if err := x.extract(zf, name, lineEnding(name, opts.eol)); err != nil {
+			return nil, err
+		}
+		if !zf.FileInfo().IsDir() {
+			extracted = append(extracted, path.Clean(name))
+		}
+	}
+	if finish != nil {
+		if !x.ownDir {
+			// The files which finish adds to a merged directory are
+			// removed along with the extracted ones.
+			var before map[string]bool
+			if before, err = listPaths(target); err != nil {
+				return nil, err
+			}
+			defer func() {
+				if err != nil {
+					removeAdded(target, before)
+				}
+			}()
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := finish(target); err != nil {
+			return nil, err
+		}
+	}
+	if target != dir {
+		if err := replaceDir(target, dir); err != nil {
+			return nil, err
+		}
+	}
+	return extracted, nil
+}
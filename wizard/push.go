@@ -0,0 +1,244 @@
+package wizard
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// gitHost is a git hosting service where the repository of the project
+// can be created and pushed to.
+type gitHost struct {
+	// tokenEnv is the environment variable of the access token.
+	tokenEnv string
+	// user is the user of the basic authentication of git with the
+	// token as the password.
+	user string
+	// create creates the repository, owned by the namespace if it is
+	// not empty, and returns its https url.
+	create func(ctx context.Context, client *http.Client, token, namespace string, r newRepo) (string, error)
+}
+
+// newRepo describes the repository to create.
+type newRepo struct {
+	name        string
+	description string
+	private     bool
+}
+
+var gitHosts = map[string]gitHost{
+	"github": {tokenEnv: "GITHUB_TOKEN", user: "x-access-token", create: createGitHubRepo},
+	"gitlab": {tokenEnv: "GITLAB_TOKEN", user: "oauth2", create: createGitLabRepo},
+}
+
+// checkPush checks that the repository can be created on the host,
+// before the project is generated.
+func checkPush(host string) error {
+	h, ok := gitHosts[host]
+	if !ok {
+		return fmt.Errorf("invalid -push '%s', should be github or gitlab", host)
+	}
+	if os.Getenv(h.tokenEnv) == "" {
+		return fmt.Errorf("-push %s needs an access token in %s", host, h.tokenEnv)
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("-push needs git: %w", err)
+	}
+	return nil
+}
+
+// pushProject commits the project in the directory, creates its
+// repository on the host and pushes the commit to it. The repository
+// is named after the artifact unless the name is given as repo, which
+// may have the owner too, e.g. acme/orders. It returns the url of the
+// repository. Canceling the context stops the requests and git.
+func pushProject(ctx context.Context, client *http.Client, dir string, info *projectInfo, host, repo string, public bool) (string, error) {
+	h, ok := gitHosts[host]
+	if !ok {
+		return "", fmt.Errorf("unknown git host '%s'", host)
+	}
+	token := os.Getenv(h.tokenEnv)
+
+	// The project may be extracted into a clone which has a remote.
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := runGit(ctx, dir, nil, "init"); err != nil {
+			return "", err
+		}
+	} else if runGit(ctx, dir, nil, "remote", "get-url", "origin") == nil {
+		return "", fmt.Errorf("%s already has a remote origin", dir)
+	}
+	if err := runGit(ctx, dir, nil, "add", "-A"); err != nil {
+		return "", err
+	}
+	if err := runGit(ctx, dir, nil, "commit", "-m", "Initial commit"); err != nil {
+		return "", err
+	}
+
+	namespace, name := "", repo
+	if i := strings.LastIndex(repo, "/"); i >= 0 {
+		namespace, name = repo[:i], repo[i+1:]
+	}
+	if name == "" {
+		name = info.artifact
+	}
+	u, err := h.create(ctx, client, token, namespace, newRepo{
+		name:        name,
+		description: info.description,
+		private:     !public,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := runGit(ctx, dir, nil, "remote", "add", "origin", u); err != nil {
+		return "", err
+	}
+	// The token is passed in the environment so that it is neither
+	// saved in the configuration of the repository nor shown in the
+	// arguments of the process.
+	auth := base64.StdEncoding.EncodeToString([]byte(h.user + ":" + token))
+	env := []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + auth,
+	}
+	if err := runGit(ctx, dir, env, "push", "-u", "origin", "HEAD"); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(u, ".git"), nil
+}
+
+// runGit runs git in the directory with the additional environment.
+func runGit(ctx context.Context, dir string, env []string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// apiURL returns the url of the api of a host, which is taken from the
+// environment variable for self-hosted instances.
+func apiURL(env, def string) string {
+	if u := os.Getenv(env); u != "" {
+		return strings.TrimSuffix(u, "/")
+	}
+	return def
+}
+
+// createGitHubRepo creates the repository of the user of the token, or
+// of the organization.
+func createGitHubRepo(ctx context.Context, client *http.Client, token, org string, r newRepo) (string, error) {
+	u := apiURL("GITHUB_API_URL", "https://api.github.com") + "/user/repos"
+	if org != "" {
+		u = apiURL("GITHUB_API_URL", "https://api.github.com") + "/orgs/" + url.PathEscape(org) + "/repos"
+	}
+	var created struct {
+		CloneURL string `json:"clone_url"`
+	}
+	err := postJSON(ctx, client, u, map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/vnd.github+json",
+	}, map[string]interface{}{
+		"name":        r.name,
+		"description": r.description,
+		"private":     r.private,
+	}, &created)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the repository on GitHub: %w", err)
+	}
+	return created.CloneURL, nil
+}
+
+// createGitLabRepo creates the project of the user of the token, or
+// in the group.
+func createGitLabRepo(ctx context.Context, client *http.Client, token, group string, r newRepo) (string, error) {
+	base := apiURL("GITLAB_URL", "https://gitlab.com") + "/api/v4"
+	headers := map[string]string{"PRIVATE-TOKEN": token}
+	visibility := "public"
+	if r.private {
+		visibility = "private"
+	}
+	body := map[string]interface{}{
+		"name":        r.name,
+		"path":        r.name,
+		"description": r.description,
+		"visibility":  visibility,
+	}
+	if group != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/namespaces/"+url.PathEscape(group), nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("PRIVATE-TOKEN", token)
+		var ns struct {
+			ID int `json:"id"`
+		}
+		if err := doJSON(client, req, &ns); err != nil {
+			return "", fmt.Errorf("failed to find the group %s on GitLab: %w", group, err)
+		}
+		body["namespace_id"] = ns.ID
+	}
+
+	var created struct {
+		HTTPURL string `json:"http_url_to_repo"`
+	}
+	if err := postJSON(ctx, client, base+"/projects", headers, body, &created); err != nil {
+		return "", fmt.Errorf("failed to create the project on GitLab: %w", err)
+	}
+	return created.HTTPURL, nil
+}
+
+// postJSON posts the body as json and decodes the response into v.
+func postJSON(ctx context.Context, client *http.Client, u string, headers map[string]string, body, v interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, val := range headers {
+		req.Header.Set(k, val)
+	}
+	return doJSON(client, req, v)
+}
+
+// doJSON sends the request and decodes the json response into v. The
+// message of the api is returned as the error of a failed request.
+func doJSON(client *http.Client, req *http.Request, v interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message interface{} `json:"message"`
+		}
+		if json.Unmarshal(b, &apiErr) == nil && apiErr.Message != nil {
+			return fmt.Errorf("%s: %v", resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return json.Unmarshal(b, v)
+}
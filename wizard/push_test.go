@@ -0,0 +1,24 @@
+package wizard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateRepoStopsWhenCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the repository was created after the push was canceled")
+	}))
+	defer srv.Close()
+	t.Setenv("GITHUB_API_URL", srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := createGitHubRepo(ctx, srv.Client(), "token", "", newRepo{name: "demo"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want the cancellation", err)
+	}
+}
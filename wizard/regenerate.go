@@ -0,0 +1,176 @@
+package wizard
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// runRegenerate runs the regenerate command which generates the
+// project again at another boot version and merges the changes of
+// the build files into the current ones.
+func runRegenerate(args []string) {
+	fs := flag.NewFlagSet("regenerate", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory of the project")
+	bootVersion := fs.String("boot-version", "",
+		"spring boot version to upgrade to, e.g. 3.4.x for the latest 3.4 release")
+	write := fs.Bool("write", false, "write the merged build files instead of showing the diff")
+	var httpOpts httpOptions
+	httpFlags(fs, &httpOpts)
+	fs.Parse(args)
+	if *bootVersion == "" {
+		die(fmt.Errorf("-boot-version is required"))
+	}
+
+	mf, err := readManifest(*dir)
+	if err != nil {
+		die(err)
+	}
+	if err := mf.checkServer(); err != nil {
+		die(err)
+	}
+	client, err := newClient(httpOpts)
+	if err != nil {
+		die(err)
+	}
+	data, err := getMetaData(client)
+	if err != nil {
+		die(err)
+	}
+	version := resolveBootVersion(data, *bootVersion)
+
+	baseArchive, base, err := fetchArchive(client.HTTPClient, mf.Server+mf.Action, mf.Params)
+	if err != nil {
+		die(err)
+	}
+	params := url.Values{}
+	for k, v := range mf.Params {
+		params[k] = v
+	}
+	params.Set("bootVersion", version)
+	upgradedArchive, upgraded, err := fetchArchive(client.HTTPClient, mf.Server+mf.Action, params)
+	if err != nil {
+		die(err)
+	}
+
+	var names []string
+	for name := range upgraded {
+		if buildFiles[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	conflicts := 0
+	for _, name := range names {
+		path := filepath.Join(*dir, filepath.FromSlash(name))
+		current, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			die(err)
+		}
+
+		theirs, err := readZipFile(upgraded[name])
+		if err != nil {
+			die(err)
+		}
+		// A build file which is new in the upgraded project is
+		// merged against an empty base.
+		var baseContent string
+		if zf, ok := base[name]; ok {
+			if baseContent, err = readZipFile(zf); err != nil {
+				die(err)
+			}
+		}
+
+		merged, n := merge3(baseContent, string(current), theirs, "current", version)
+		conflicts += n
+		if *write {
+			if err := os.WriteFile(path, []byte(merged), 0644); err != nil {
+				die(err)
+			}
+			continue
+		}
+		fmt.Print(unifiedDiff(name, name+" ("+version+")", string(current), merged))
+	}
+	baseArchive.remove()
+	upgradedArchive.remove()
+
+	if *write {
+		mf.BootVersion = version
+		mf.Params = params
+		if err := writeManifest(*dir, *mf); err != nil {
+			die(err)
+		}
+		fmt.Printf("Upgraded the build files to %s\n", version)
+	}
+	if conflicts > 0 {
+		fmt.Println(warnStyle.Render(fmt.Sprintf(
+			"%d conflicts between your changes and the upgrade are marked with <<<<<<<", conflicts)))
+		exit(1)
+	}
+}
+
+// preRelease matches the snapshots, the milestones and the release
+// candidates of spring boot, e.g. 3.5.0-SNAPSHOT, 3.5.0-M1 or
+// 2.7.0.RC1.
+var preRelease = regexp.MustCompile(`SNAPSHOT|[.-](M|RC)\d+$`)
+
+func isPreRelease(v string) bool {
+	return preRelease.MatchString(v)
+}
+
+// resolveBootVersion resolves a version pattern like 3.4.x to the
+// latest release of the server which matches it, or to its latest
+// milestone or snapshot if there is no release yet, latest to the newest
+// version of the server and latest-stable to the newest one which is
+// not a pre-release. Other versions are returned as they are.
+func resolveBootVersion(data *metadata, v string) string {
+	switch v {
+	case "latest":
+		if len(data.BootVersion.Values) > 0 {
+			return data.BootVersion.Values[0].Id
+		}
+		return v
+	case "latest-stable":
+		for _, bv := range data.BootVersion.Values {
+			if !isPreRelease(bv.Id) {
+				return bv.Id
+			}
+		}
+		return v
+	}
+
+	prefix := strings.TrimSuffix(v, "x")
+	if prefix == v {
+		return v
+	}
+	// Without a release, a milestone is preferred to a snapshot.
+	preview, snapshot := "", ""
+	for _, bv := range data.BootVersion.Values {
+		switch {
+		case !strings.HasPrefix(bv.Id, prefix):
+		case !isPreRelease(bv.Id):
+			return bv.Id
+		case strings.Contains(bv.Id, "SNAPSHOT"):
+			if snapshot == "" {
+				snapshot = bv.Id
+			}
+		case preview == "":
+			preview = bv.Id
+		}
+	}
+	switch {
+	case preview != "":
+		return preview
+	case snapshot != "":
+		return snapshot
+	}
+	return v
+}
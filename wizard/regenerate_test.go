@@ -0,0 +1,29 @@
+package wizard
+
+import (
+	"testing"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+func TestResolveBootVersion(t *testing.T) {
+	data := &metadata{}
+	for _, id := range []string{"3.5.0-SNAPSHOT", "3.5.0-RC1", "3.5.0-M1", "3.4.1-SNAPSHOT", "3.4.0", "3.3.6", "2.7.0.RC1"} {
+		data.BootVersion.Values = append(data.BootVersion.Values, initializr.Value{Id: id})
+	}
+	tests := []struct{ in, want string }{
+		{"3.4.x", "3.4.0"},
+		{"3.5.x", "3.5.0-RC1"},
+		{"3.3.x", "3.3.6"},
+		{"2.7.x", "2.7.0.RC1"},
+		{"4.0.x", "4.0.x"},
+		{"latest", "3.5.0-SNAPSHOT"},
+		{"latest-stable", "3.4.0"},
+		{"3.3.6", "3.3.6"},
+	}
+	for _, tt := range tests {
+		if got := resolveBootVersion(data, tt.in); got != tt.want {
+			t.Errorf("resolveBootVersion(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
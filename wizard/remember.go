@@ -0,0 +1,95 @@
+package wizard
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// lastAnswers are the answers of the last project generated with the
+// form, which rarely change between the projects of a company.
+type lastAnswers struct {
+	GroupId     string `yaml:"groupId,omitempty"`
+	Language    string `yaml:"language,omitempty"`
+	JavaVersion string `yaml:"javaVersion,omitempty"`
+	Packaging   string `yaml:"packaging,omitempty"`
+	Type        string `yaml:"type,omitempty"`
+}
+
+// lastAnswersFile returns the file of the last answers, e.g.
+// ~/.config/startspring/last.yaml.
+func lastAnswersFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "startspring", "last.yaml"), nil
+}
+
+// rememberAnswers makes the last answers the defaults of the form
+// instead of the ones of the server. An answer which the server does
+// not offer anymore is ignored, and so is a missing or broken file as
+// the answers are only a convenience.
+func rememberAnswers(data *metadata) {
+	path, err := lastAnswersFile()
+	if err != nil {
+		return
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var last lastAnswers
+	if err := yaml.Unmarshal(b, &last); err != nil {
+		return
+	}
+
+	if last.GroupId != "" {
+		data.GroupId.Default = last.GroupId
+	}
+	for _, f := range []struct {
+		field *initializr.SelectField
+		val   string
+	}{
+		{&data.Language, last.Language},
+		{&data.JavaVersion, last.JavaVersion},
+		{&data.Packaging, last.Packaging},
+	} {
+		for _, v := range f.field.Values {
+			if v.Id == f.val {
+				f.field.Default = v.Id
+			}
+		}
+	}
+	for _, pt := range data.ProjectType.Values {
+		if pt.Id == last.Type && pt.Tags["format"] == "project" {
+			data.ProjectType.Default = pt.Id
+		}
+	}
+}
+
+// saveLastAnswers saves the answers of the project which are
+// remembered for the next run.
+func saveLastAnswers(info *projectInfo) error {
+	b, err := yaml.Marshal(lastAnswers{
+		GroupId:     info.group,
+		Language:    info.language,
+		JavaVersion: info.javaVersion,
+		Packaging:   info.packaging,
+		Type:        info.projectType,
+	})
+	if err != nil {
+		return err
+	}
+	path, err := lastAnswersFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
@@ -0,0 +1,57 @@
+package wizard
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// reproducibleTime returns the modification time of the files of
+// a reproducible project. It is taken from SOURCE_DATE_EPOCH if
+// set, otherwise the earliest time a zip archive can hold is used.
+func reproducibleTime() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+	return time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// normalizeTree sets the same modification time and normalized
+// permissions on every file of the directory so that two projects
+// generated from the same request are identical.
+func normalizeTree(dir string) error {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	mtime := reproducibleTime()
+	// Children are visited before their directory as changing a
+	// child updates the modification time of the directory.
+	for i := len(paths) - 1; i >= 0; i-- {
+		info, err := os.Lstat(paths[i])
+		if err != nil {
+			return err
+		}
+		mode := fs.FileMode(0644)
+		if info.IsDir() || info.Mode()&0111 != 0 {
+			mode = 0755
+		}
+		if err := os.Chmod(paths[i], mode); err != nil {
+			return err
+		}
+		if err := os.Chtimes(paths[i], mtime, mtime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
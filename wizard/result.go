@@ -0,0 +1,58 @@
+package wizard
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// result is the summary of a generated project for the scripts which
+// wrap startspring.
+type result struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	BootVersion  string   `json:"bootVersion"`
+	Dependencies []string `json:"dependencies"`
+	// Dir is the absolute path of the project directory, or of the
+	// directory of the build file if only the build file is
+	// generated.
+	Dir string `json:"dir"`
+	// Files are the sorted slash separated paths of the files which
+	// were written, relative to Dir. The files which were in the
+	// directory before, e.g. with -existing merge, are left out.
+	Files []string `json:"files"`
+}
+
+// writeResult writes the summary of the project generated in dir as
+// json to the file, or to stdout if it is "-". The files are the ones
+// which the generation wrote into dir.
+func writeResult(path string, info *projectInfo, dir string, files []string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	files = append([]string{}, files...)
+	sort.Strings(files)
+	deps := info.dependencies
+	if deps == nil {
+		deps = []string{}
+	}
+	b, err := json.MarshalIndent(result{
+		Name:         info.name,
+		Type:         info.projectType,
+		BootVersion:  info.bootVersion,
+		Dependencies: deps,
+		Dir:          dir,
+		Files:        files,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if path == "-" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
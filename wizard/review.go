@@ -0,0 +1,200 @@
+package wizard
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// reviewSection is a section of the review screen. The sections are
+// in the order of the groups of the form, so that a section is
+// edited in its group.
+type reviewSection struct {
+	title string
+	lines []string
+}
+
+// reviewSections returns the answers of the form with the defaults
+// filled in.
+func (m model) reviewSections() []reviewSection {
+	info := m.info.clone()
+	applyDefaults(info, m.data)
+
+	project := []string{fmt.Sprintf("%s · %s:%s:%s", info.name, info.group, info.artifact, info.version)}
+	if info.description != "" {
+		project = append(project, info.description)
+	}
+	if pkg := info.resolvedPackageName(); pkg != "" {
+		project = append(project, "package "+pkg)
+	}
+	if dir, err := filepath.Abs(info.dir()); err == nil {
+		project = append(project, "into "+dir)
+	}
+
+	var typeName string
+	for _, pv := range m.data.ProjectType.Values {
+		if pv.Id == info.projectType {
+			typeName = pv.Name
+		}
+	}
+	build := []string{
+		fmt.Sprintf("Spring Boot %s · Java %s",
+			valueName(m.data.BootVersion.Values, info.bootVersion), info.javaVersion),
+		fmt.Sprintf("%s · %s · %s", typeName,
+			valueName(m.data.Language.Values, info.language), info.packaging),
+	}
+
+	var deps []string
+	for _, values := range m.data.Dependencies.Values {
+		for _, dep := range values.Values {
+			for _, id := range info.dependencies {
+				if id == dep.Id {
+					deps = append(deps, dep.Name)
+				}
+			}
+		}
+	}
+	for _, p := range m.catalogProjects {
+		deps = append(deps, p.Name+" (catalog)")
+	}
+	if len(deps) == 0 {
+		deps = []string{"none"}
+	}
+
+	var names []string
+	for _, id := range info.addOns {
+		for _, a := range addOns {
+			if a.id == id {
+				names = append(names, a.name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		names = []string{"none"}
+	}
+
+	sections := []reviewSection{
+		{"Project", project},
+		{"Build", build},
+		{"Dependencies", []string{strings.Join(deps, ", ")}},
+		{"Add-ons", names},
+	}
+	if len(m.data.Extra) > 0 {
+		var params []string
+		for k, v := range info.extra {
+			if *v != "" {
+				params = append(params, k+"="+*v)
+			}
+		}
+		sort.Strings(params)
+		if len(params) == 0 {
+			params = []string{"defaults of the server"}
+		}
+		sections = append(sections, reviewSection{"Additional options", []string{strings.Join(params, ", ")}})
+	}
+	return sections
+}
+
+// valueName returns the name of the value with the id, or the id if
+// there is none.
+func valueName(values []initializr.Value, id string) string {
+	for _, v := range values {
+		if v.Id == id {
+			return v.Name
+		}
+	}
+	return id
+}
+
+func (m model) reviewView() string {
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render("Review the project"))
+	sb.WriteString("\n\n")
+	for i, s := range m.reviewSections() {
+		cursor := "  "
+		title := fmt.Sprintf("%-20s", s.title)
+		if i == m.reviewCursor {
+			cursor = cursorStyle.Render("> ")
+			title = cursorStyle.Render(title)
+		}
+		for j, line := range s.lines {
+			if j > 0 {
+				cursor, title = "  ", strings.Repeat(" ", 20)
+			}
+			fmt.Fprintf(&sb, "%s%s%s\n", cursor, title, line)
+		}
+	}
+	if m.estimatedSize > 0 {
+		fmt.Fprintf(&sb, "\n  %-20s%s\n", "Download size", "about "+formatSize(m.estimatedSize))
+	}
+	if len(m.preflight) > 0 {
+		sb.WriteString("\n")
+		for _, w := range m.preflight {
+			sb.WriteString(warnStyle.Render("! "+w) + "\n")
+		}
+	}
+	sb.WriteString("\n")
+	hint := "enter generate • ↑/↓ select • e edit • p preview • q abort"
+	// Only an archive can be explored.
+	if m.data.isBuildType(m.info.projectType) {
+		hint = "enter generate • ↑/↓ select • e edit • q abort"
+	}
+	if m.notice != "" {
+		sb.WriteString(hintStyle.Render(m.notice) + "\n")
+	}
+	sb.WriteString(hintStyle.Render(hint))
+	return sb.String()
+}
+
+func (m model) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.notice = ""
+	switch msg.String() {
+	case "enter", "y":
+		return m.generate()
+	case "up", "k":
+		if m.reviewCursor > 0 {
+			m.reviewCursor--
+		}
+	case "down", "j":
+		if m.reviewCursor < len(m.reviewSections())-1 {
+			m.reviewCursor++
+		}
+	case "e":
+		return m.edit(m.reviewCursor)
+	case "p":
+		if m.data.isBuildType(m.info.projectType) {
+			return m, nil
+		}
+		m.state = stateSpinner
+		m.startCancelable()
+		wait := m.trackProgress()
+		return m, tea.Batch(m.spin(m.previewProject()), wait)
+	case "q", "esc":
+		m.finalMsg = "Aborted, the project was not generated"
+		m.state = stateDone
+		return m, m.quit(true)
+	}
+	return m, nil
+}
+
+// edit goes back to the form at the group of the section with the
+// answers given so far.
+func (m model) edit(section int) (tea.Model, tea.Cmd) {
+	for _, p := range m.catalogProjects {
+		m.info.dependencies = append(m.info.dependencies, catalogPrefix+p.Name)
+	}
+	m.catalogProjects = nil
+
+	m.form, m.deps = newForm(m.info, m.data, m.opts)
+	cmds := []tea.Cmd{m.form.Init()}
+	for i := 0; i < section; i++ {
+		cmds = append(cmds, m.form.NextGroup())
+	}
+	m.state = stateForm
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,129 @@
+package wizard
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// coordinatesMsg holds the maven coordinates of the dependencies,
+// e.g. org.postgresql:postgresql, keyed by their ids.
+type coordinatesMsg map[string]string
+
+// coordinatesCmd waits for the dependencies which are prefetched
+// along with the metadata and returns their coordinates, which the
+// search of the picker matches too.
+func coordinatesCmd(data *metadata) tea.Cmd {
+	return func() tea.Msg {
+		deps, ok := data.prefetchedDependencies(data.BootVersion.Default)
+		if !ok {
+			return nil
+		}
+		return coordinatesMsg(depCoordinates(deps))
+	}
+}
+
+// depCoordinates returns the coordinates of the resolved dependencies
+// keyed by their ids.
+func depCoordinates(deps *initializr.Dependencies) map[string]string {
+	coords := make(map[string]string)
+	for id, dep := range deps.Dependencies {
+		coords[id] = dep.GroupId + ":" + dep.ArtifactId
+	}
+	return coords
+}
+
+// matchScore returns how well the option matches the search, 0 if it
+// does not. Every word of the search must match the id, the name, the
+// coordinates, the category or the description, in the order of their
+// scores. The name also matches fuzzily, e.g. pgsql matches PostgreSQL
+// Driver.
+func matchScore(query string, o depOption, coordinates string) int {
+	score := 0
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		s := wordScore(word, o, coordinates)
+		if s == 0 {
+			return 0
+		}
+		score += s
+	}
+	return score
+}
+
+func wordScore(word string, o depOption, coordinates string) int {
+	id, label := strings.ToLower(o.id), strings.ToLower(o.label)
+	switch {
+	case id == word || label == word:
+		return 100
+	case strings.HasPrefix(id, word) || strings.HasPrefix(label, word) ||
+		strings.Contains(label, " "+word):
+		return 80
+	case strings.Contains(id, word) || strings.Contains(label, word):
+		return 60
+	case strings.Contains(strings.ToLower(coordinates), word):
+		return 40
+	case strings.Contains(strings.ToLower(o.group), word):
+		return 30
+	case strings.Contains(strings.ToLower(o.description), word):
+		return 20
+	}
+	return fuzzyScore(word, label)
+}
+
+// fuzzyScore matches the letters of the word in order in the text,
+// and scores the closer matches higher, from 1 to 10. The short words
+// do not match fuzzily as they would match almost everything.
+func fuzzyScore(word, text string) int {
+	if len(word) < 3 {
+		return 0
+	}
+	first, last := -1, -1
+	i := 0
+	for j := 0; j < len(text) && i < len(word); j++ {
+		if text[j] != word[i] {
+			continue
+		}
+		if first < 0 {
+			first = j
+		}
+		last = j
+		i++
+	}
+	if i < len(word) {
+		return 0
+	}
+	score := 10 * len(word) / (last - first + 1)
+	if score < 1 {
+		score = 1
+	}
+	return score
+}
+
+// sortMatches orders the matches by their scores, keeping the ones
+// of a category together. The categories come in the order of their
+// best match.
+func sortMatches(matches []depOption, scores map[string]int) {
+	best := make(map[string]int)
+	order := make(map[string]int)
+	for _, o := range matches {
+		if _, ok := order[o.group]; !ok {
+			order[o.group] = len(order)
+		}
+		if scores[o.id] > best[o.group] {
+			best[o.group] = scores[o.id]
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if best[a.group] != best[b.group] {
+			return best[a.group] > best[b.group]
+		}
+		if order[a.group] != order[b.group] {
+			return order[a.group] < order[b.group]
+		}
+		return scores[a.id] > scores[b.id]
+	})
+}
@@ -0,0 +1,150 @@
+package wizard
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// shareURL returns the url which opens the web UI of the server,
+// e.g. start.spring.io, with all the selections of the given project.
+func shareURL(server string, info *projectInfo) string {
+	params := []struct{ key, val string }{
+		{"type", info.projectType},
+		{"language", info.language},
+		{"platformVersion", info.bootVersion},
+		{"packaging", info.packaging},
+		{"jvmVersion", info.javaVersion},
+		{"groupId", info.group},
+		{"artifactId", info.artifact},
+		{"version", info.version},
+		{"name", strings.TrimSpace(info.name)},
+		{"description", info.description},
+		{"packageName", info.resolvedPackageName()},
+		{"dependencies", strings.Join(info.dependencies, ",")},
+	}
+
+	var sb strings.Builder
+	for _, p := range params {
+		if p.val == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('&')
+		}
+		sb.WriteString(p.key)
+		sb.WriteByte('=')
+		sb.WriteString(url.QueryEscape(p.val))
+	}
+	return server + "/#!" + sb.String()
+}
+
+// curlCommand returns the curl command which requests the same
+// project archive from the server.
+func curlCommand(server string, info *projectInfo, action string) string {
+	form := projectForm(info)
+	var keys []string
+	for k := range form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString("curl " + shellQuote(server+action))
+	for _, k := range keys {
+		for _, v := range form[k] {
+			flag := "-d"
+			if url.QueryEscape(v) != v {
+				flag = "--data-urlencode"
+			}
+			sb.WriteString(" " + flag + " " + shellQuote(k+"="+v))
+		}
+	}
+	output := info.resolvedBaseDir() + ".zip"
+	// The build file keeps its own name, e.g. pom.xml.
+	if !strings.HasSuffix(action, ".zip") {
+		output = path.Base(action)
+	}
+	sb.WriteString(" -o " + shellQuote(output))
+	return sb.String()
+}
+
+// shellQuote quotes the word for a POSIX shell if it has any
+// special character.
+func shellQuote(s string) string {
+	safe := s != ""
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-.,:/=@+", r)) {
+			safe = false
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shareText returns the share link and the curl command of the
+// project on the server with the defaults filled in.
+func shareText(server string, info *projectInfo, data *metadata) (string, error) {
+	info = info.clone()
+	applyDefaults(info, data)
+	action, err := data.generateAction(info.projectType)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Share link:\n%s\n\ncurl command:\n%s", shareURL(server, info), curlCommand(server, info, action)), nil
+}
+
+// openBrowser opens the given url in the default browser.
+func openBrowser(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	return cmd.Start()
+}
+
+// qrCode renders the given text as a QR code which can be
+// printed to the terminal. Each line of the output holds two
+// rows of the code using half block characters.
+func qrCode(text string) (string, error) {
+	qr, err := qrcode.New(text, qrcode.Low)
+	if err != nil {
+		return "", err
+	}
+	bitmap := qr.Bitmap()
+
+	var sb strings.Builder
+	for y := 0; y < len(bitmap); y += 2 {
+		for x := range bitmap[y] {
+			// Dark modules are left blank so that the code stays
+			// readable on a dark terminal.
+			top := !bitmap[y][x]
+			bottom := y+1 < len(bitmap) && !bitmap[y+1][x]
+			switch {
+			case top && bottom:
+				sb.WriteRune('█')
+			case top:
+				sb.WriteRune('▀')
+			case bottom:
+				sb.WriteRune('▄')
+			default:
+				sb.WriteRune(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String(), nil
+}
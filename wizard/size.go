@@ -0,0 +1,147 @@
+package wizard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// maxSizeSamples is the number of the archive sizes which are kept
+// for each type and language of the project.
+const maxSizeSamples = 20
+
+// sizeSample is the size of a downloaded archive with the number
+// of its dependencies.
+type sizeSample struct {
+	Dependencies int   `json:"dependencies"`
+	Size         int64 `json:"size"`
+}
+
+// sizeMsg is sent with the estimated size of the project archive.
+type sizeMsg struct{ size int64 }
+
+// sizeHistoryFile returns the file where the sizes of the previous
+// downloads are kept.
+func sizeHistoryFile() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "startspring", "sizes.json"), nil
+}
+
+// readSizeHistory reads the archive sizes keyed by the type and the
+// language of the project. A missing or broken file is an empty
+// history.
+func readSizeHistory() map[string][]sizeSample {
+	history := make(map[string][]sizeSample)
+	path, err := sizeHistoryFile()
+	if err != nil {
+		return history
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return history
+	}
+	json.Unmarshal(b, &history)
+	return history
+}
+
+func sizeKey(info *projectInfo) string {
+	return info.projectType + "/" + info.language
+}
+
+// recordSize adds the size of the downloaded archive to the history.
+func recordSize(info *projectInfo, size int64) error {
+	path, err := sizeHistoryFile()
+	if err != nil {
+		return err
+	}
+	history := readSizeHistory()
+	key := sizeKey(info)
+	samples := append(history[key], sizeSample{len(info.dependencies), size})
+	if len(samples) > maxSizeSamples {
+		samples = samples[len(samples)-maxSizeSamples:]
+	}
+	history[key] = samples
+
+	b, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// estimateSize returns the size of the previous download of the
+// same type and language with the closest number of dependencies.
+// Without any history, the size is asked from the server with a
+// HEAD request. It returns 0 if the size is unknown.
+func estimateSize(client *initializr.Client, action string, info *projectInfo) int64 {
+	var best *sizeSample
+	for _, s := range readSizeHistory()[sizeKey(info)] {
+		s := s
+		// The later samples win the ties as they are more recent.
+		if best == nil || abs(s.Dependencies-len(info.dependencies)) <= abs(best.Dependencies-len(info.dependencies)) {
+			best = &s
+		}
+	}
+	if best != nil {
+		return best.Size
+	}
+
+	u := client.URL + action + "?" + projectForm(info).Encode()
+	resp, err := client.HTTPClient.Head(u)
+	if err != nil {
+		return 0
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.ContentLength < 0 {
+		return 0
+	}
+	return resp.ContentLength
+}
+
+// estimateSizeCmd estimates the size of the archive while the project
+// is reviewed. It works on a copy of the project as the generation
+// may fill in the defaults meanwhile.
+func estimateSizeCmd(client *initializr.Client, data *metadata, info *projectInfo) tea.Cmd {
+	return func() tea.Msg {
+		applyDefaults(info, data)
+		action, err := data.generateAction(info.projectType)
+		if err != nil {
+			return nil
+		}
+		return sizeMsg{estimateSize(client, action, info)}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// formatSize formats the size in bytes with a binary unit, e.g.
+// 56.3 KB.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMG"[exp])
+}
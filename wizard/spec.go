@@ -0,0 +1,144 @@
+package wizard
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// specFile is the default spec file of the generate command.
+const specFile = "startspring.yaml"
+
+// spec is a file which describes the projects to generate, e.g.
+//
+//	defaults:
+//	  groupId: com.acme
+//	  bootVersion: 3.3.x
+//	projects:
+//	  - name: orders
+//	    dependencies: [web, data-jpa]
+//	  - name: billing
+//
+// The projects have the keys of a configuration file and the
+// defaults fill in the ones which a project leaves out. The artifact
+// id is the name of the project if it is not given.
+type spec struct {
+	Defaults config   `yaml:"defaults,omitempty"`
+	Projects []config `yaml:"projects"`
+}
+
+// readSpec reads the projects of the spec file.
+func readSpec(path string) ([]*projectInfo, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s spec
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+	if len(s.Projects) == 0 {
+		return nil, fmt.Errorf("invalid spec %s: no projects", path)
+	}
+
+	defaults := s.Defaults.projectInfo()
+	var infos []*projectInfo
+	for _, cfg := range s.Projects {
+		info := cfg.projectInfo()
+		info.fill(defaults)
+		// The projects would otherwise share the default artifact
+		// id of the server.
+		if info.artifact == "" {
+			info.artifact = info.name
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// runGenerate runs the generate command which generates every project
+// of a spec file without any prompt.
+func runGenerate(args []string) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: startspring generate [flags]")
+		fs.PrintDefaults()
+	}
+	path := fs.String("f", specFile, "spec `file` which describes the projects to generate")
+	var opts options
+	fs.StringVar(&opts.existing, "existing", existingFail,
+		"what to do if the directory of a project is not empty: fail, overwrite or merge")
+	fs.BoolVar(&opts.reproducible, "reproducible", false,
+		"normalize the timestamps and the permissions of the extracted files")
+	httpFlags(fs, &opts.http)
+	fs.Parse(args)
+
+	switch opts.existing {
+	case existingFail, existingOverwrite, existingMerge:
+	default:
+		die(fmt.Errorf("invalid -existing '%s', should be fail, overwrite or merge", opts.existing))
+	}
+
+	infos, err := readSpec(*path)
+	if err != nil {
+		die(err)
+	}
+	client, err := newClient(opts.http)
+	if err != nil {
+		die(err)
+	}
+	data, err := getMetaData(client)
+	if err != nil {
+		die(err)
+	}
+
+	// Every project is checked before any of them is generated, so
+	// that a mistake in the spec does not leave half of them.
+	dirs := make(map[string]bool)
+	for _, info := range infos {
+		applyDefaults(info, data)
+		info.bootVersion = resolveBootVersion(data, info.bootVersion)
+		dir, err := filepath.Abs(info.dir())
+		if err != nil {
+			die(err)
+		}
+		if dirs[dir] {
+			die(fmt.Errorf("%s: more than one project is generated in %s", info.name, info.dir()))
+		}
+		dirs[dir] = true
+		if err := checkHeadless(data, info, opts.existing); err != nil {
+			die(fmt.Errorf("%s: %w", info.name, err))
+		}
+	}
+
+	// ctrl+c cancels the generation, which removes what it
+	// extracted.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	for _, info := range infos {
+		m := newModel(data, client, info, opts)
+		m.ctx = ctx
+		switch msg := m.generateProject()().(type) {
+		case errMsg:
+			die(fmt.Errorf("%s: %w", info.name, msg.err))
+		case generatedMsg:
+			for _, w := range msg.warnings {
+				fmt.Fprintln(os.Stderr, warnStyle.Render(info.name+": "+w))
+			}
+			if msg.buildFile != "" {
+				fmt.Printf("Wrote the build file of %s to %s\n", info.name, msg.buildFile)
+				continue
+			}
+		}
+		fmt.Printf("Generated %s in %s\n", info.name, info.dir())
+	}
+}
@@ -0,0 +1,204 @@
+package wizard
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"unicode"
+)
+
+// keywords are the reserved words of the languages which can not
+// be used as a segment of a package name.
+var keywords = map[string][]string{
+	"java": {
+		"abstract", "assert", "boolean", "break", "byte", "case", "catch",
+		"char", "class", "const", "continue", "default", "do", "double",
+		"else", "enum", "extends", "false", "final", "finally", "float",
+		"for", "goto", "if", "implements", "import", "instanceof", "int",
+		"interface", "long", "native", "new", "null", "package", "private",
+		"protected", "public", "return", "short", "static", "strictfp",
+		"super", "switch", "synchronized", "this", "throw", "throws",
+		"transient", "true", "try", "void", "volatile", "while", "_",
+	},
+	// Kotlin only reserves its hard keywords, so e.g. int or
+	// native can be used in a package name.
+	"kotlin": {
+		"as", "break", "class", "continue", "do", "else", "false", "for",
+		"fun", "if", "in", "interface", "is", "null", "object", "package",
+		"return", "super", "this", "throw", "true", "try", "typealias",
+		"typeof", "val", "var", "when", "while",
+	},
+	"groovy": {
+		"abstract", "as", "assert", "boolean", "break", "byte", "case",
+		"catch", "char", "class", "const", "continue", "def", "default",
+		"do", "double", "else", "enum", "extends", "false", "final",
+		"finally", "float", "for", "goto", "if", "implements", "import",
+		"in", "instanceof", "int", "interface", "long", "native", "new",
+		"null", "package", "private", "protected", "public", "return",
+		"short", "static", "strictfp", "super", "switch", "synchronized",
+		"this", "threadsafe", "throw", "throws", "trait", "transient",
+		"true", "try", "var", "void", "volatile", "while",
+	},
+}
+
+// isKeyword checks if the word is reserved in the language. Java
+// keywords are used for an unknown language.
+func isKeyword(word, language string) bool {
+	words, ok := keywords[language]
+	if !ok {
+		words = keywords["java"]
+	}
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+// derivePackageName derives a valid package name of the language from
+// the group and the artifact id, e.g. com.example and my-app become
+// com.example.myapp.
+func derivePackageName(group, artifact, language string) string {
+	var parts []string
+	for _, part := range strings.Split(group+"."+artifact, ".") {
+		var sb strings.Builder
+		for _, r := range strings.ToLower(part) {
+			if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				sb.WriteRune(r)
+			}
+		}
+		part = sb.String()
+		if part == "" {
+			continue
+		}
+		if unicode.IsDigit(rune(part[0])) || isKeyword(part, language) {
+			part = "_" + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// validatePackageName checks if the package name is valid in the
+// given language.
+func validatePackageName(pkg, language string) error {
+	if pkg == "" {
+		return nil
+	}
+	for _, part := range strings.Split(pkg, ".") {
+		if part == "" {
+			return fmt.Errorf("package name '%s' has an empty segment", pkg)
+		}
+		for i, r := range part {
+			valid := r == '_' || unicode.IsLetter(r) ||
+				i > 0 && unicode.IsDigit(r) ||
+				r == '$' && language != "kotlin"
+			if !valid {
+				return fmt.Errorf("package name '%s' has an invalid character '%c'", pkg, r)
+			}
+		}
+		if isKeyword(part, language) {
+			return fmt.Errorf("'%s' is a reserved keyword in %s and can not be in the package name",
+				part, languageName(language))
+		}
+	}
+	return nil
+}
+
+// languageName returns the display name of the language id.
+func languageName(language string) string {
+	if language == "" {
+		language = "java"
+	}
+	return strings.ToUpper(language[:1]) + language[1:]
+}
+
+// validateMavenId checks that the id only has the characters which
+// Maven allows in a group or an artifact id.
+func validateMavenId(kind, id string) error {
+	for _, r := range id {
+		valid := r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) ||
+			strings.ContainsRune("-_.", r)
+		if !valid {
+			return fmt.Errorf("%s '%s' has an invalid character '%c', use letters, digits, '-', '_' and '.'",
+				kind, id, r)
+		}
+	}
+	return nil
+}
+
+// validateGroupId checks that the group id is a valid Maven id made
+// of dot separated segments, e.g. com.example.
+func validateGroupId(group string) error {
+	if err := validateMavenId("group id", group); err != nil {
+		return err
+	}
+	if group == "" {
+		return nil
+	}
+	for _, seg := range strings.Split(group, ".") {
+		if seg == "" {
+			return fmt.Errorf("group id '%s' has an empty segment", group)
+		}
+	}
+	return nil
+}
+
+// validateArtifactId checks that the artifact id is a valid Maven id.
+func validateArtifactId(artifact string) error {
+	return validateMavenId("artifact id", artifact)
+}
+
+// artifactWarning returns a warning if the artifact id has uppercase
+// letters, which Maven allows but the conventions and some
+// repositories do not.
+func artifactWarning(artifact string) string {
+	if strings.ToLower(artifact) == artifact {
+		return ""
+	}
+	return fmt.Sprintf("The artifact id '%s' has uppercase letters, "+
+		"the Maven conventions and some repositories expect lowercase ones", artifact)
+}
+
+// validateFileName checks that the name can be the name of a
+// directory on the current OS.
+func validateFileName(name string) error {
+	if name == "." || name == ".." {
+		return fmt.Errorf("'%s' can not be the name of a directory", name)
+	}
+	illegal := "/\x00"
+	switch runtime.GOOS {
+	case "windows":
+		illegal += `\`
+	case "darwin":
+		illegal += ":"
+	}
+	if i := strings.IndexAny(name, illegal); i >= 0 {
+		return fmt.Errorf("'%s' has the character '%c' which is not allowed in a directory name", name, name[i])
+	}
+	if runtime.GOOS == "windows" {
+		return checkWindowsName(name)
+	}
+	return nil
+}
+
+// validateCoordinates checks the name, the group and the artifact id
+// of the project and the package name which is sent to the server,
+// the given one or the one derived from them.
+func validateCoordinates(info *projectInfo) error {
+	if err := validateGroupId(info.group); err != nil {
+		return err
+	}
+	if err := validateArtifactId(info.artifact); err != nil {
+		return err
+	}
+	// The name is the directory of the project unless a base
+	// directory is given.
+	if strings.TrimSpace(info.baseDir) == "" {
+		if err := validateFileName(strings.TrimSpace(info.name)); err != nil {
+			return err
+		}
+	}
+	return validatePackageName(info.resolvedPackageName(), info.language)
+}
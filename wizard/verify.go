@@ -0,0 +1,137 @@
+package wizard
+
+import (
+	"archive/zip"
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// buildFiles are the files which configure the build of a project.
+var buildFiles = map[string]bool{
+	"pom.xml": true, "build.gradle": true, "build.gradle.kts": true,
+	"settings.gradle": true, "settings.gradle.kts": true,
+	".mvn/wrapper/maven-wrapper.properties":    true,
+	"gradle/wrapper/gradle-wrapper.properties": true,
+}
+
+// runVerify runs the verify command which generates the project
+// again from its manifest and reports the files which drifted
+// from the original ones.
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory of the project")
+	all := fs.Bool("all", false, "verify all the files instead of only the build files")
+	var httpOpts httpOptions
+	httpFlags(fs, &httpOpts)
+	fs.Parse(args)
+
+	mf, err := readManifest(*dir)
+	if err != nil {
+		die(err)
+	}
+	if err := mf.checkServer(); err != nil {
+		die(err)
+	}
+	client, err := newClient(httpOpts)
+	if err != nil {
+		die(err)
+	}
+
+	a, files, err := fetchArchive(client.HTTPClient, mf.Server+mf.Action, mf.Params)
+	if err != nil {
+		die(err)
+	}
+	// The files of the add-ons differ from the generated ones on
+	// purpose.
+	changed := make(map[string]bool)
+	for _, name := range mf.AddOns {
+		changed[name] = true
+	}
+	var names []string
+	for name := range files {
+		if (*all || buildFiles[name]) && !changed[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	drifted := false
+	for _, name := range names {
+		want, err := readZipFile(files[name])
+		if err != nil {
+			die(err)
+		}
+		got, err := os.ReadFile(filepath.Join(*dir, filepath.FromSlash(name)))
+		switch {
+		case os.IsNotExist(err):
+			fmt.Printf("%s %s is missing\n", failStyle.Render("✗"), name)
+			drifted = true
+		case err != nil:
+			die(err)
+		// The line endings may be converted on extraction.
+		case !bytes.Equal(convertEOL(got, eolLF), convertEOL([]byte(want), eolLF)):
+			fmt.Printf("%s %s is modified\n", failStyle.Render("✗"), name)
+			drifted = true
+		default:
+			fmt.Printf("%s %s\n", okStyle.Render("✓"), name)
+		}
+	}
+	a.remove()
+	if drifted {
+		exit(1)
+	}
+}
+
+// fetchArchive generates the project with the request and returns
+// its archive, which the caller removes, and the files of the
+// archive. Like download, it streams the archive into a temporary file.
+func fetchArchive(client *http.Client, u string, params url.Values) (*archive, map[string]*zip.File, error) {
+	resp, err := client.PostForm(u, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("failed to generate project: %s", resp.Status)
+	}
+	a, err := newArchive(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	files, err := archiveFiles(a, params.Get("baseDir"))
+	if err != nil {
+		a.remove()
+		return nil, nil, err
+	}
+	return a, files, nil
+}
+
+// archiveFiles returns the files of the project archive keyed by
+// their path relative to the base directory.
+func archiveFiles(a *archive, baseDir string) (map[string]*zip.File, error) {
+	zipReader, err := a.reader()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File)
+	for _, zf := range zipReader.File {
+		if zf.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(zf.Name)
+		if baseDir != "" {
+			name = strings.TrimPrefix(name, filepath.ToSlash(baseDir)+"/")
+		}
+		files[name] = zf
+	}
+	return files, nil
+}
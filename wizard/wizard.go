@@ -0,0 +1,177 @@
+// Package wizard is the terminal UI of startspring which asks the
+// questions of a Spring Boot project and generates it with the Spring
+// Initializr.
+//
+// The wizard can be embedded in another bubbletea program:
+//
+//	w, err := wizard.New(wizard.Options{
+//		Project: wizard.Project{Group: "com.acme"},
+//		OnExit: func(r wizard.Result) tea.Cmd {
+//			return func() tea.Msg { return wizardDoneMsg(r) }
+//		},
+//	})
+//
+// The host forwards its messages to the returned model until OnExit
+// is called.
+package wizard
+
+import (
+	"net/http"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// Project is a Spring project. The empty values are filled with the
+// defaults of the server.
+type Project struct {
+	Name         string
+	Group        string
+	Artifact     string
+	Version      string
+	Description  string
+	PackageName  string
+	BaseDir      string
+	Type         string
+	Language     string
+	BootVersion  string
+	Packaging    string
+	JavaVersion  string
+	Dependencies []string
+	// AddOns are the ids of the add-ons applied after extraction,
+	// e.g. dotenv.
+	AddOns []string
+
+	// Params holds the values of the additional fields declared by
+	// the server, keyed by the id of the field.
+	Params map[string]string
+}
+
+// Result is the outcome of the wizard.
+type Result struct {
+	// Project holds the answers of the form.
+	Project Project
+	// Dir is the absolute directory of the generated project. It is
+	// empty if the project was not generated.
+	Dir string
+	// Err is the error which stopped the generation.
+	Err error
+	// Canceled is set if the user quit the wizard.
+	Canceled bool
+}
+
+// Options configures the wizard.
+type Options struct {
+	// Client sends the requests to the server. http.DefaultClient is
+	// used if it is nil.
+	Client *http.Client
+	// Server is the url of the Spring Initializr server, e.g. a
+	// self-hosted one. start.spring.io is used if it is empty.
+	Server string
+	// Project holds the initial answers of the form.
+	Project Project
+	// Plain uses a compact and colorless UI.
+	Plain bool
+	// SkipGeneration ends the wizard once the form is completed
+	// without generating the project.
+	SkipGeneration bool
+
+	// OnSubmit is called with the answers once the form is completed.
+	OnSubmit func(Project) tea.Cmd
+	// OnExit is called when the wizard ends. The program is quit if
+	// it is nil.
+	OnExit func(Result) tea.Cmd
+}
+
+// New fetches the metadata of the server and returns the wizard as a
+// model which can be run with tea.NewProgram or embedded in another
+// model. The project is generated in the current directory.
+func New(opts Options) (tea.Model, error) {
+	server, err := parseServer(opts.Server)
+	if err != nil {
+		return nil, err
+	}
+	client := &initializr.Client{HTTPClient: opts.Client, URL: server}
+	if client.HTTPClient == nil {
+		client.HTTPClient = http.DefaultClient
+	}
+	data, err := fetchMetadata(client)
+	if err != nil {
+		return nil, err
+	}
+
+	info := newProjectInfo(opts.Project)
+	m := newModel(data, client, info, options{plain: opts.Plain})
+	m.hooks.skipGeneration = opts.SkipGeneration
+	if opts.OnSubmit != nil {
+		m.hooks.submit = func(info *projectInfo) tea.Cmd {
+			return opts.OnSubmit(info.project())
+		}
+	}
+	if opts.OnExit != nil {
+		m.hooks.exit = func(m model, canceled bool) tea.Cmd {
+			return opts.OnExit(Result{
+				Project:  m.info.project(),
+				Dir:      m.projectDir,
+				Err:      m.err,
+				Canceled: canceled,
+			})
+		}
+	}
+	return m, nil
+}
+
+func newProjectInfo(p Project) *projectInfo {
+	info := &projectInfo{
+		name:         p.Name,
+		group:        p.Group,
+		artifact:     p.Artifact,
+		version:      p.Version,
+		description:  p.Description,
+		packageName:  p.PackageName,
+		baseDir:      p.BaseDir,
+		projectType:  p.Type,
+		language:     p.Language,
+		bootVersion:  p.BootVersion,
+		packaging:    p.Packaging,
+		javaVersion:  p.JavaVersion,
+		dependencies: append([]string(nil), p.Dependencies...),
+		addOns:       append([]string(nil), p.AddOns...),
+	}
+	for k, v := range p.Params {
+		v := v
+		if info.extra == nil {
+			info.extra = make(map[string]*string)
+		}
+		info.extra[k] = &v
+	}
+	return info
+}
+
+// project returns the exported copy of the project.
+func (info *projectInfo) project() Project {
+	p := Project{
+		Name:         info.name,
+		Group:        info.group,
+		Artifact:     info.artifact,
+		Version:      info.version,
+		Description:  info.description,
+		PackageName:  info.packageName,
+		BaseDir:      info.baseDir,
+		Type:         info.projectType,
+		Language:     info.language,
+		BootVersion:  info.bootVersion,
+		Packaging:    info.packaging,
+		JavaVersion:  info.javaVersion,
+		Dependencies: append([]string(nil), info.dependencies...),
+		AddOns:       append([]string(nil), info.addOns...),
+	}
+	for k, v := range info.extra {
+		if p.Params == nil {
+			p.Params = make(map[string]string)
+		}
+		p.Params[k] = *v
+	}
+	return p
+}
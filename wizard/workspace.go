@@ -0,0 +1,362 @@
+package wizard
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/nhAnik/startspring/pkg/initializr"
+)
+
+// workspaceModule is a module of a workspace with the dependencies
+// which only it needs.
+type workspaceModule struct {
+	name string
+	deps []string
+}
+
+// wrapperFiles are the files of the build tool wrappers, which are
+// shared by the modules from the root of the workspace.
+var wrapperFiles = map[string][]string{
+	"maven":  {"mvnw", "mvnw.cmd", ".mvn"},
+	"gradle": {"gradlew", "gradlew.bat", "gradle"},
+}
+
+// runWorkspace runs the workspace command which generates several
+// modules under a parent directory with a root build which includes
+// them, an aggregator pom.xml or a Gradle settings file.
+func runWorkspace(args []string) {
+	info := &projectInfo{}
+
+	fs := flag.NewFlagSet("workspace", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: startspring workspace [flags]")
+		fmt.Fprintln(fs.Output(),
+			"The workspace is created in the base directory, the name if empty. The modules share the project flags.")
+		fs.PrintDefaults()
+	}
+	projectFlags(fs, info)
+	var modules moduleFlag
+	fs.Var(&modules, "module",
+		"name=deps of a module with its comma separated dependency ids, e.g. api=web, can be repeated; asked for if none")
+	existing := fs.String("existing", existingFail,
+		"what to do if the workspace directory is not empty: fail, overwrite or merge")
+	plain := fs.Bool("plain", false, "use a compact and colorless UI for limited terminals")
+	var httpOpts httpOptions
+	httpFlags(fs, &httpOpts)
+	fs.Parse(args)
+
+	switch *existing {
+	case existingFail, existingOverwrite, existingMerge:
+	default:
+		die(fmt.Errorf("invalid -existing '%s', should be fail, overwrite or merge", *existing))
+	}
+
+	client, err := newClient(httpOpts)
+	if err != nil {
+		die(err)
+	}
+	data, err := getMetaData(client)
+	if err != nil {
+		die(err)
+	}
+	applyDefaults(info, data)
+	info.bootVersion = resolveBootVersion(data, info.bootVersion)
+	build, err := workspaceBuild(data, info.projectType)
+	if err != nil {
+		die(err)
+	}
+
+	root := info.resolvedBaseDir()
+	if err := checkDir(root, *existing); err != nil {
+		die(err)
+	}
+	if len(modules) == 0 {
+		if modules, err = askModules(client, data, info, *plain); err != nil {
+			die(err)
+		}
+	}
+
+	opts := options{existing: *existing}
+	var infos []*projectInfo
+	for i, mod := range modules {
+		for _, prev := range modules[:i] {
+			if prev.name == mod.name {
+				die(fmt.Errorf("the module %s is given twice", mod.name))
+			}
+		}
+		mi := moduleInfo(info, root, mod)
+		if err := checkHeadless(data, mi, opts.existing); err != nil {
+			die(fmt.Errorf("module %s: %w", mod.name, err))
+		}
+		infos = append(infos, mi)
+	}
+	// An overwritten workspace is generated next to the old one,
+	// which is only replaced once every module is generated.
+	target, err := prepareDir(root, *existing)
+	if err != nil {
+		die(err)
+	}
+	fail := func(err error) {
+		if target != root {
+			os.RemoveAll(target)
+		}
+		die(err)
+	}
+	// ctrl+c cancels the generation, which removes what it
+	// extracted.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var names []string
+	for _, mi := range infos {
+		mi.output = filepath.Join(target, mi.name)
+		m := newModel(data, client, mi, opts)
+		m.ctx = ctx
+		switch msg := m.generateProject()().(type) {
+		case errMsg:
+			fail(fmt.Errorf("module %s: %w", mi.name, msg.err))
+		case generatedMsg:
+			for _, w := range msg.warnings {
+				fmt.Fprintln(os.Stderr, warnStyle.Render(mi.name+": "+w))
+			}
+		}
+		names = append(names, mi.name)
+		fmt.Printf("Generated the module %s in %s\n", mi.name, filepath.Join(root, mi.name))
+	}
+
+	if err := hoistWrapper(target, names, build); err != nil {
+		fail(err)
+	}
+	file, content := rootBuild(info, names, build, data.ProjectType.Values)
+	if err := os.WriteFile(filepath.Join(target, file), []byte(content), 0644); err != nil {
+		fail(err)
+	}
+	if target != root {
+		if err := replaceDir(target, root); err != nil {
+			fail(err)
+		}
+	}
+	fmt.Printf("Wrote %s which includes %s\n", filepath.Join(root, file), strings.Join(names, ", "))
+}
+
+// workspaceBuild returns the build tool of the project type, which
+// must be a maven or a gradle project.
+func workspaceBuild(data *metadata, projectType string) (string, error) {
+	for _, pv := range data.ProjectType.Values {
+		if pv.Id != projectType {
+			continue
+		}
+		if pv.Tags["format"] != "project" {
+			return "", fmt.Errorf("a workspace needs a project type, not '%s'", projectType)
+		}
+		if _, ok := wrapperFiles[pv.Tags["build"]]; !ok {
+			return "", fmt.Errorf("a workspace needs a maven or gradle project, not '%s'", projectType)
+		}
+		return pv.Tags["build"], nil
+	}
+	return "", fmt.Errorf("unknown project type '%s'", projectType)
+}
+
+// moduleInfo returns the project of the module in the workspace. The
+// module is named after itself and has the dependencies shared by
+// all the modules besides its own.
+func moduleInfo(info *projectInfo, root string, mod workspaceModule) *projectInfo {
+	mi := info.clone()
+	mi.name = mod.name
+	mi.artifact = mod.name
+	mi.baseDir = mod.name
+	mi.packageName = ""
+	mi.output = filepath.Join(root, mod.name)
+	for _, id := range mod.deps {
+		if !contains(mi.dependencies, id) {
+			mi.dependencies = append(mi.dependencies, id)
+		}
+	}
+	return mi
+}
+
+func contains(values []string, v string) bool {
+	for _, val := range values {
+		if val == v {
+			return true
+		}
+	}
+	return false
+}
+
+// askModules asks the names of the modules and then the dependencies
+// of each of them.
+func askModules(client *initializr.Client, data *metadata, info *projectInfo, plain bool) ([]workspaceModule, error) {
+	var names string
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewInput().
+			Title("Modules").
+			Description("Comma separated names of the modules, e.g. api,core,worker").
+			Value(&names).
+			Validate(func(str string) error {
+				_, err := parseModuleNames(str)
+				return err
+			}),
+	))
+	if plain {
+		form = form.WithTheme(plainTheme()).WithShowHelp(false)
+	} else {
+		form = form.WithTheme(huh.ThemeDracula())
+	}
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+	modNames, _ := parseModuleNames(names)
+
+	deps, err := getDependencies(client, info.bootVersion)
+	if err != nil {
+		return nil, err
+	}
+	shared := func(id string) bool {
+		return contains(info.dependencies, id)
+	}
+	var modules []workspaceModule
+	for _, name := range modNames {
+		description := "Spring Boot " + info.bootVersion
+		if len(info.dependencies) > 0 {
+			description += ", every module has " + strings.Join(info.dependencies, ", ")
+		}
+		ids, err := pickDependencies(data, deps, info.bootVersion, shared,
+			"Dependencies of "+name, description, plain)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, workspaceModule{name: name, deps: ids})
+	}
+	return modules, nil
+}
+
+// parseModuleNames parses the comma separated names of the modules.
+func parseModuleNames(s string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if err := validateModuleName(name); err != nil {
+			return nil, err
+		}
+		if contains(names, name) {
+			return nil, fmt.Errorf("the module %s is given twice", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// validateModuleName checks that the name can be both the directory
+// and the artifact id of a module.
+func validateModuleName(name string) error {
+	if name == "" {
+		return errors.New("the name of a module can not be empty")
+	}
+	for i, r := range name {
+		valid := r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+		if !valid && (i == 0 || !strings.ContainsRune("-_.", r)) {
+			return fmt.Errorf("invalid module name '%s', use letters, digits, '-', '_' and '.'", name)
+		}
+	}
+	return nil
+}
+
+// hoistWrapper moves the build tool wrapper of the first module to
+// the root of the workspace and removes the ones of the others. The
+// settings files of gradle modules are removed too, as the settings
+// file of the root includes them.
+func hoistWrapper(root string, modules []string, build string) error {
+	for _, mod := range modules {
+		for _, name := range wrapperFiles[build] {
+			src := filepath.Join(root, mod, name)
+			if _, err := os.Lstat(src); os.IsNotExist(err) {
+				continue
+			}
+			dst := filepath.Join(root, name)
+			if _, err := os.Lstat(dst); os.IsNotExist(err) {
+				if err := os.Rename(src, dst); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := os.RemoveAll(src); err != nil {
+				return err
+			}
+		}
+		if build == "gradle" {
+			for _, name := range []string{"settings.gradle", "settings.gradle.kts"} {
+				if err := os.Remove(filepath.Join(root, mod, name)); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// rootBuild returns the name and the content of the root build file
+// of the workspace: an aggregator pom.xml for maven, or the settings
+// file in the dialect of the project type for gradle.
+func rootBuild(info *projectInfo, modules []string, build string, types []initializr.ProjectType) (string, string) {
+	var sb strings.Builder
+	if build == "maven" {
+		sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+	xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 https://maven.apache.org/xsd/maven-4.0.0.xsd">
+	<modelVersion>4.0.0</modelVersion>
+`)
+		for _, el := range []struct{ tag, val string }{
+			{"groupId", info.group},
+			{"artifactId", info.resolvedBaseDir()},
+			{"version", info.version},
+			{"packaging", "pom"},
+			{"name", info.name},
+		} {
+			fmt.Fprintf(&sb, "\t<%s>%s</%s>\n", el.tag, xmlEscape(el.val), el.tag)
+		}
+		sb.WriteString("\t<modules>\n")
+		for _, mod := range modules {
+			fmt.Fprintf(&sb, "\t\t<module>%s</module>\n", mod)
+		}
+		sb.WriteString("\t</modules>\n</project>\n")
+		return "pom.xml", sb.String()
+	}
+
+	kotlin := false
+	for _, pt := range types {
+		kotlin = kotlin || pt.Id == info.projectType && pt.Tags["dialect"] == "kotlin"
+	}
+	if kotlin {
+		fmt.Fprintf(&sb, "rootProject.name = %q\n", info.resolvedBaseDir())
+		fmt.Fprintf(&sb, "include(%s)\n", quoteAll(modules, `"`))
+		return "settings.gradle.kts", sb.String()
+	}
+	fmt.Fprintf(&sb, "rootProject.name = '%s'\n", info.resolvedBaseDir())
+	fmt.Fprintf(&sb, "include %s\n", quoteAll(modules, "'"))
+	return "settings.gradle", sb.String()
+}
+
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+// quoteAll quotes the values and joins them with commas.
+func quoteAll(values []string, quote string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote + v + quote
+	}
+	return strings.Join(quoted, ", ")
+}